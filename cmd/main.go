@@ -3,21 +3,42 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
 	"simple-service/internal/api"
+	"simple-service/internal/api/handlers"
+	"simple-service/internal/api/middleware"
+	"simple-service/internal/auth"
 	"simple-service/internal/config"
+	"simple-service/internal/grpcapi"
 	customLogger "simple-service/internal/logger"
+	"simple-service/internal/metrics"
+	"simple-service/internal/reminder"
 	"simple-service/internal/repo"
 	"simple-service/internal/service"
+	"simple-service/internal/tracing"
 )
 
 func main() {
+	// Подкоманда управления миграциями
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Загружаем конфигурацию из переменных окружения
 	var cfg config.AppConfig
 	if err := envconfig.Process("", &cfg); err != nil {
@@ -30,17 +51,68 @@ func main() {
 		log.Fatal(errors.Wrap(err, "error initializing logger"))
 	}
 
+	// Экспорт трейсов OpenTelemetry
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "failed to initialize tracing"))
+	}
+
+	// Применение миграций при запуске, реплики ждут друг друга на advisory lock
+	if cfg.AutoMigrate {
+		applied, err := autoMigrate(context.Background(), cfg.PostgreSQL)
+		if err != nil {
+			log.Fatal(errors.Wrap(err, "failed to apply migrations"))
+		}
+		for _, m := range applied {
+			logger.Infof("Applied migration %06d_%s", m.Version, m.Name)
+		}
+	}
+
 	// Подключение к PostgreSQL
 	repository, err := repo.NewRepository(context.Background(), cfg.PostgreSQL)
 	if err != nil {
 		log.Fatal(errors.Wrap(err, "failed to initialize repository"))
 	}
 
-	// Создание сервиса с бизнес-логикой
-	serviceInstance := service.NewService(repository, logger)
+	// Статистика пула соединений для /metrics
+	prometheus.MustRegister(metrics.NewPoolCollector(repository.Stat))
+
+	// Создание сервиса с бизнес-логикой и HTTP-обработчиков поверх него
+	tasks := service.NewTaskService(repository)
+	taskHandlers := handlers.New(tasks, logger)
+
+	// Проверка токенов для выбранного режима (статические токены или JWT), общая для HTTP и gRPC
+	authenticator, err := auth.NewAuthenticator(cfg.Rest)
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "failed to initialize authorization"))
+	}
+	authorization := middleware.Authenticate(authenticator)
 
 	// Инициализация API
-	app := api.NewRouters(&api.Routers{Service: serviceInstance}, cfg.Rest.Token)
+	health := api.NewHealth(repository, logger)
+	app := api.NewRouters(&api.Routers{
+		Handlers:  taskHandlers,
+		Health:    health,
+		Log:       logger,
+		AccessLog: cfg.AccessLog,
+		RateLimit: cfg.RateLimit,
+
+		IdempotencyStore:       repository,
+		IdempotencyTTL:         cfg.Idempotency.TTL,
+		IdempotencyLockTimeout: cfg.Idempotency.LockTimeout,
+	}, authorization)
+
+	// Фоновые задачи останавливаются вместе с сервисом
+	background, stopBackground := context.WithCancel(context.Background())
+
+	// Периодическое удаление просроченных ключей идемпотентности
+	go purgeIdempotencyKeys(background, repository, cfg.Idempotency.PurgeInterval, logger)
+
+	// Отправка напоминаний о сроках задач
+	if cfg.Reminders.Enabled {
+		scheduler := reminder.NewScheduler(repository, reminder.NewLogNotifier(logger), cfg.Reminders, logger)
+		go scheduler.Run(background)
+	}
 
 	// Запуск HTTP-сервера в отдельной горутине
 	go func() {
@@ -50,10 +122,120 @@ func main() {
 		}
 	}()
 
+	// Запуск gRPC-сервера на отдельном порту
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		listener, err := net.Listen("tcp", cfg.GRPC.ListenAddress)
+		if err != nil {
+			log.Fatal(errors.Wrap(err, "failed to listen gRPC address"))
+		}
+		grpcServer = grpcapi.NewServer(tasks, authenticator, logger)
+
+		go func() {
+			logger.Infof("Starting gRPC server on %s", cfg.GRPC.ListenAddress)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatal(errors.Wrap(err, "failed to start gRPC server"))
+			}
+		}()
+	}
+
 	// Ожидание системных сигналов для корректного завершения работы
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
 	<-signalChan
 
 	logger.Info("Shutting down gracefully...")
+
+	// Сначала /readyz начинает отвечать ошибкой, и балансировщик успевает убрать реплику
+	health.Drain()
+	time.Sleep(cfg.ShutdownDelay)
+
+	// Перестаём принимать соединения и ждём завершения текущих запросов HTTP и gRPC
+	// параллельно, чтобы вся остановка укладывалась в один ShutdownTimeout
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	var (
+		wg          sync.WaitGroup
+		httpErr     error
+		grpcDrained = true
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		httpErr = app.ShutdownWithContext(drainCtx)
+	}()
+	if grpcServer != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			grpcDrained = gracefulStop(drainCtx, grpcServer)
+		}()
+	}
+	wg.Wait()
+	cancelDrain()
+
+	exitCode := 0
+	if httpErr != nil {
+		logger.Errorw("Failed to drain in-flight requests", "error", httpErr)
+		exitCode = 1
+	}
+	if !grpcDrained {
+		logger.Error("Failed to drain in-flight gRPC calls")
+		exitCode = 1
+	}
+
+	// Запросов больше нет, фоновые задачи и соединения с БД можно остановить
+	stopBackground()
+	repository.Close()
+
+	// Отправляем оставшиеся span, ограничивая ожидание тем же таймаутом
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Errorw("Failed to flush traces", "error", err)
+	}
+	cancel()
+
+	// Сбрасываем буферизованные записи лога перед выходом
+	_ = logger.Sync()
+
+	os.Exit(exitCode)
+}
+
+// purgeIdempotencyKeys - удаление просроченных ключей идемпотентности раз в interval до отмены ctx
+func purgeIdempotencyKeys(ctx context.Context, repository repo.Repository, interval time.Duration, logger *zap.SugaredLogger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := repository.PurgeIdempotencyKeys(ctx)
+			if err != nil {
+				logger.Errorw("Failed to purge idempotency keys", "error", err)
+				continue
+			}
+			if purged > 0 {
+				logger.Infof("Purged %d expired idempotency keys", purged)
+			}
+		}
+	}
+}
+
+// gracefulStop - ожидание завершения текущих вызовов gRPC до отмены ctx,
+// после чего оставшиеся соединения закрываются. Возвращает false, если время истекло
+func gracefulStop(ctx context.Context, srv *grpc.Server) bool {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		srv.Stop()
+		return false
+	}
 }
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+
+	"simple-service/internal/config"
+	"simple-service/internal/migrate"
+	"simple-service/migrations"
+)
+
+// Подкоманда migrate: применение, откат и просмотр состояния миграций
+
+const migrateUsage = `usage: simple-service migrate <command>
+
+commands:
+  up          apply all pending migrations
+  down [N]    roll back the last N applied migrations (default 1)
+  baseline V  mark migrations up to version V as applied without running them
+  status      list migrations and whether they are applied`
+
+// runMigrate - выполнение подкоманды migrate с аргументами args
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	// Для миграций нужны только параметры подключения к БД
+	var cfg config.PostgreSQL
+	if err := envconfig.Process("", &cfg); err != nil {
+		return errors.Wrap(err, "failed to load configuration")
+	}
+
+	ctx := context.Background()
+	migrator, err := migrate.Connect(ctx, cfg, migrations.Postgres())
+	if err != nil {
+		return err
+	}
+	defer migrator.Close(ctx)
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %06d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("rolled back %06d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "baseline":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil || version < 1 {
+			return fmt.Errorf("invalid migration version %q", args[1])
+		}
+		marked, err := migrator.Baseline(ctx, version)
+		for _, m := range marked {
+			fmt.Printf("marked %06d_%s as applied\n", m.Version, m.Name)
+		}
+		if err == nil && len(marked) == 0 {
+			fmt.Println("no migrations to mark")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			applied := "pending"
+			if st.AppliedAt != nil {
+				applied = "applied " + st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%06d_%s\t%s\n", st.Version, st.Name, applied)
+		}
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+// autoMigrate - применение миграций при запуске сервиса, если это включено в конфигурации
+func autoMigrate(ctx context.Context, cfg config.PostgreSQL) ([]migrate.Migration, error) {
+	migrator, err := migrate.Connect(ctx, cfg, migrations.Postgres())
+	if err != nil {
+		return nil, err
+	}
+	defer migrator.Close(ctx)
+
+	return migrator.Up(ctx)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"simple-service/pkg/client"
+)
+
+// Команды клиента
+
+// command - выполнение команды с аргументами args, результат печатается в stdout
+type command func(ctx context.Context, args []string, stdout io.Writer) error
+
+var commands = map[string]command{
+	"add":  addCommand,
+	"get":  getCommand,
+	"ls":   listCommand,
+	"done": doneCommand,
+}
+
+// addCommand - todo add "title" [-d description] [--due T] [--remind T]
+func addCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	var s settings
+	fs := newFlagSet("add", &s)
+	description := fs.String("d", "", "task description")
+	due := fs.String("due", "", "due time, RFC 3339 or local \"YYYY-MM-DD HH:MM\"")
+	remind := fs.String("remind", "", "reminder time, not later than --due")
+
+	positional, err := parseArgs(fs, args, &s)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return &usageError{msg: `usage: todo add "title" [-d description] [--due T] [--remind T]`}
+	}
+	input := client.TaskInput{Title: positional[0], Description: *description}
+	if input.DueAt, err = parseTime("due", *due); err != nil {
+		return err
+	}
+	if input.RemindAt, err = parseTime("remind", *remind); err != nil {
+		return err
+	}
+
+	api, err := newClient(s)
+	if err != nil {
+		return err
+	}
+	created, err := api.CreateTask(ctx, input, client.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	if s.Output == outputJSON {
+		return printJSON(stdout, map[string]int{"task_id": created.TaskID})
+	}
+	fmt.Fprintln(stdout, created.TaskID)
+	return nil
+}
+
+// getCommand - todo get <id>
+func getCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	var s settings
+	fs := newFlagSet("get", &s)
+
+	positional, err := parseArgs(fs, args, &s)
+	if err != nil {
+		return err
+	}
+	taskID, err := taskIDArg(positional, "usage: todo get <id>")
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient(s)
+	if err != nil {
+		return err
+	}
+	task, err := api.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if s.Output == outputJSON {
+		return printJSON(stdout, task)
+	}
+	return printTask(stdout, task)
+}
+
+// listCommand - todo ls [--status S] [--overdue] [--sort F] [--limit N] [--cursor C]
+func listCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	var s settings
+	fs := newFlagSet("ls", &s)
+	status := fs.String("status", "", "filter by status: new, in_progress or done")
+	sort := fs.String("sort", "", "sort field, prefix with - for descending order")
+	limit := fs.Int("limit", 0, "page size, 1 to 100")
+	cursor := fs.String("cursor", "", "cursor of the next page")
+	overdue := fs.Bool("overdue", false, "only unfinished tasks past their due time")
+
+	positional, err := parseArgs(fs, args, &s)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return &usageError{msg: "usage: todo ls [--status S] [--overdue] [--sort F] [--limit N] [--cursor C]"}
+	}
+
+	api, err := newClient(s)
+	if err != nil {
+		return err
+	}
+	list, err := api.ListTasks(ctx, client.ListOptions{
+		Limit:   *limit,
+		Sort:    *sort,
+		Status:  client.TaskStatus(*status),
+		Cursor:  *cursor,
+		Overdue: *overdue,
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.Output == outputJSON {
+		return printJSON(stdout, struct {
+			Tasks      []client.Task `json:"tasks"`
+			NextCursor string        `json:"next_cursor,omitempty"`
+		}{Tasks: list.Tasks, NextCursor: list.NextCursor})
+	}
+	return printTasks(stdout, list.Tasks, list.NextCursor)
+}
+
+// doneCommand - todo done <id>
+func doneCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	var s settings
+	fs := newFlagSet("done", &s)
+
+	positional, err := parseArgs(fs, args, &s)
+	if err != nil {
+		return err
+	}
+	taskID, err := taskIDArg(positional, "usage: todo done <id>")
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient(s)
+	if err != nil {
+		return err
+	}
+	status, err := api.TransitionTask(ctx, taskID, client.StatusDone, false)
+	if err != nil {
+		return err
+	}
+
+	if s.Output == outputJSON {
+		return printJSON(stdout, map[string]any{"task_id": taskID, "status": status})
+	}
+	fmt.Fprintf(stdout, "task %d is %s\n", taskID, status)
+	return nil
+}
+
+// parseTime - время из флага: RFC 3339 или местное время без зоны; пустое значение - nil
+func parseTime(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+	if err != nil {
+		return nil, &usageError{msg: fmt.Sprintf("invalid --%s %q, expected RFC 3339 or \"YYYY-MM-DD HH:MM\"", name, value)}
+	}
+	return &t, nil
+}
+
+// newFlagSet - набор флагов команды с общими флагами; ошибки разбора возвращаются, а не завершают процесс
+func newFlagSet(name string, s *settings) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	addCommonFlags(fs, s)
+	return fs
+}
+
+// parseArgs - разбор флагов, которые могут стоять и до, и после позиционных аргументов
+// (todo add "title" -d desc), и настроек клиента
+func parseArgs(fs *flag.FlagSet, args []string, s *settings) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, &usageError{msg: err.Error()}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if err := s.resolve(); err != nil {
+		return nil, err
+	}
+	return positional, nil
+}
+
+// newClient - клиент API с настройками команды
+func newClient(s settings) (*client.Client, error) {
+	api, err := client.New(client.Config{BaseURL: s.URL, Token: s.Token, Timeout: s.Timeout})
+	if err != nil {
+		return nil, &usageError{msg: err.Error()}
+	}
+	return api, nil
+}
+
+// taskIDArg - id задачи из единственного позиционного аргумента
+func taskIDArg(positional []string, usage string) (int, error) {
+	if len(positional) != 1 {
+		return 0, &usageError{msg: usage}
+	}
+	taskID, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return 0, &usageError{msg: fmt.Sprintf("task id must be a number, got %q", positional[0])}
+	}
+	return taskID, nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Настройки клиента: флаги важнее переменных окружения, переменные окружения - файла конфигурации
+
+const (
+	defaultURL     = "http://localhost:8080"
+	defaultTimeout = 10 * time.Second
+
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// settings - адрес сервиса, токен и формат вывода
+type settings struct {
+	URL     string
+	Token   string
+	Output  string
+	Timeout time.Duration
+
+	configPath string
+}
+
+// fileSettings - содержимое файла конфигурации
+type fileSettings struct {
+	URL    string `json:"url"`
+	Token  string `json:"token"`
+	Output string `json:"output"`
+}
+
+// addCommonFlags - флаги, общие для всех команд
+func addCommonFlags(fs *flag.FlagSet, s *settings) {
+	fs.StringVar(&s.URL, "url", "", "service URL")
+	fs.StringVar(&s.Token, "token", "", "bearer token")
+	fs.StringVar(&s.Output, "o", "", "output format: table or json")
+	fs.StringVar(&s.configPath, "config", "", "config file")
+	fs.DurationVar(&s.Timeout, "timeout", defaultTimeout, "request timeout")
+}
+
+// resolve - незаданные флагами настройки берутся из окружения, затем из файла конфигурации
+func (s *settings) resolve() error {
+	s.URL = firstNonEmpty(s.URL, os.Getenv("TODO_URL"))
+	s.Token = firstNonEmpty(s.Token, os.Getenv("TODO_TOKEN"))
+	s.Output = firstNonEmpty(s.Output, os.Getenv("TODO_OUTPUT"))
+
+	file, err := loadConfigFile(firstNonEmpty(s.configPath, os.Getenv("TODO_CONFIG")))
+	if err != nil {
+		return err
+	}
+	s.URL = firstNonEmpty(s.URL, file.URL, defaultURL)
+	s.Token = firstNonEmpty(s.Token, file.Token)
+	s.Output = firstNonEmpty(s.Output, file.Output, outputTable)
+
+	if s.Output != outputTable && s.Output != outputJSON {
+		return &usageError{msg: "output format must be table or json"}
+	}
+	return nil
+}
+
+// loadConfigFile - чтение файла конфигурации. Явно указанный файл обязан существовать,
+// файла по умолчанию может не быть
+func loadConfigFile(path string) (fileSettings, error) {
+	var file fileSettings
+
+	explicit := path != ""
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return file, nil
+		}
+		path = filepath.Join(dir, "todo", "config.json")
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return file, nil
+	}
+	if err != nil {
+		return file, errors.Wrap(err, "failed to read config file")
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file, errors.Wrapf(err, "invalid config file %s", path)
+	}
+	return file, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
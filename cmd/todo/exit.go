@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+
+	"simple-service/pkg/client"
+)
+
+// Коды выхода, по которым скрипты различают ошибки API
+
+const (
+	exitOK           = 0
+	exitError        = 1
+	exitUsage        = 2
+	exitUnauthorized = 3
+	exitForbidden    = 4
+	exitNotFound     = 5
+	exitInvalid      = 6
+	exitConflict     = 7
+	exitRateLimited  = 8
+	exitUnavailable  = 9
+)
+
+// errorExits - код выхода для каждой группы ошибок API
+var errorExits = []struct {
+	err  error
+	code int
+}{
+	{client.ErrUnauthorized, exitUnauthorized},
+	{client.ErrForbidden, exitForbidden},
+	{client.ErrNotFound, exitNotFound},
+	{client.ErrValidation, exitInvalid},
+	{client.ErrInvalidTransition, exitConflict},
+	{client.ErrVersionMismatch, exitConflict},
+	{client.ErrPreconditionRequired, exitConflict},
+	{client.ErrIdempotencyConflict, exitConflict},
+	{client.ErrRateLimited, exitRateLimited},
+	{client.ErrUnavailable, exitUnavailable},
+}
+
+// usageError - неверные аргументы команды
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// exitCode - код выхода для ошибки: неверные аргументы, ошибка API с кодом dto или прочий сбой
+func exitCode(err error) int {
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		return exitUsage
+	}
+	for _, e := range errorExits {
+		if errors.Is(err, e.err) {
+			return e.code
+		}
+	}
+	return exitError
+}
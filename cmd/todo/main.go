@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// todo - клиент командной строки для REST API задач
+
+const usage = `usage: todo <command> [flags] [args]
+
+commands:
+  add "title" [-d description]   create a task and print its id
+                                 (--due T, --remind T: RFC 3339 or local "YYYY-MM-DD HH:MM")
+  get <id>                       show a task
+  ls [--status S] [--sort F]     list tasks (--overdue, --limit N, --cursor C for the next page)
+  done <id>                      mark a task as done
+
+common flags:
+  --url URL          service URL (env TODO_URL, default http://localhost:8080)
+  --token TOKEN      bearer token (env TODO_TOKEN)
+  --config PATH      config file (env TODO_CONFIG, default <user config dir>/todo/config.json)
+  -o table|json      output format (env TODO_OUTPUT, default table)
+  --timeout D        request timeout (default 10s)
+
+exit codes:
+  0  success
+  1  unexpected error (network, bad response)
+  2  invalid command line
+  3  UNAUTHORIZED, TOKEN_EXPIRED, TOKEN_INVALID
+  4  FORBIDDEN
+  5  TASK_NOT_FOUND
+  6  FIELD_BADFORMAT, FIELD_INCORRECT
+  7  INVALID_TRANSITION, VERSION_MISMATCH, PRECONDITION_REQUIRED, IDEMPOTENCY_*
+  8  RATE_LIMITED
+  9  SERVICE_UNAVAILABLE`
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run - выполнение команды, возвращает код выхода
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprintln(stderr, usage)
+		return exitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "todo: unknown command %q\n\n%s\n", args[0], usage)
+		return exitUsage
+	}
+
+	if err := cmd(ctx, args[1:], stdout); err != nil {
+		fmt.Fprintf(stderr, "todo: %v\n", err)
+		return exitCode(err)
+	}
+	return exitOK
+}
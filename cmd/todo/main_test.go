@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"simple-service/internal/dto"
+	"simple-service/pkg/client"
+)
+
+// newTestServer - API, которое проверяет токен и отвечает на запросы handler
+func newTestServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(dto.Response{Status: "error", Error: &dto.Error{Code: dto.Unauthorized, Desc: "Invalid bearer token"}})
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// isolate - окружение без настроек пользователя, на котором запущены тесты
+func isolate(t *testing.T) {
+	t.Setenv("TODO_URL", "")
+	t.Setenv("TODO_TOKEN", "")
+	t.Setenv("TODO_OUTPUT", "")
+	t.Setenv("TODO_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+}
+
+func runTodo(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestAdd(t *testing.T) {
+	isolate(t)
+	srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/create_task", r.URL.Path)
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]string{"title": "Buy milk", "description": "2 bottles"}, body)
+
+		_ = json.NewEncoder(w).Encode(dto.Response{Status: "success", Data: map[string]int{"task_id": 12}})
+	})
+
+	t.Run("Флаги после заголовка", func(t *testing.T) {
+		code, stdout, _ := runTodo("add", "Buy milk", "-d", "2 bottles", "--url", srv.URL, "--token", "secret")
+		assert.Equal(t, exitOK, code)
+		assert.Equal(t, "12\n", stdout)
+	})
+
+	t.Run("Вывод в JSON", func(t *testing.T) {
+		t.Setenv("TODO_URL", srv.URL)
+		t.Setenv("TODO_TOKEN", "secret")
+
+		code, stdout, _ := runTodo("add", "-o", "json", "-d", "2 bottles", "Buy milk")
+		assert.Equal(t, exitOK, code)
+		assert.JSONEq(t, `{"task_id": 12}`, stdout)
+	})
+
+	t.Run("Без заголовка", func(t *testing.T) {
+		code, _, stderr := runTodo("add", "--url", srv.URL)
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, "usage: todo add")
+	})
+}
+
+func TestAddWithDueDate(t *testing.T) {
+	isolate(t)
+	srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "2026-01-02T15:00:00Z", body["due_at"])
+		assert.Equal(t, "2026-01-02T14:00:00Z", body["remind_at"])
+
+		_ = json.NewEncoder(w).Encode(dto.Response{Status: "success", Data: map[string]int{"task_id": 13}})
+	})
+
+	code, stdout, _ := runTodo("add", "Report", "--due", "2026-01-02T15:00:00Z", "--remind", "2026-01-02T14:00:00Z",
+		"--url", srv.URL, "--token", "secret")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "13\n", stdout)
+}
+
+func TestList(t *testing.T) {
+	isolate(t)
+	srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/tasks", r.URL.Path)
+		assert.Equal(t, "new", r.URL.Query().Get("status"))
+
+		_ = json.NewEncoder(w).Encode(dto.Response{
+			Status:     "success",
+			Data:       []client.Task{{ID: 1, Title: "First", Status: client.StatusNew}, {ID: 2, Title: "Second", Status: client.StatusNew}},
+			NextCursor: "abc",
+		})
+	})
+	t.Setenv("TODO_URL", srv.URL)
+	t.Setenv("TODO_TOKEN", "secret")
+
+	t.Run("Таблица", func(t *testing.T) {
+		code, stdout, _ := runTodo("ls", "--status", "new")
+		assert.Equal(t, exitOK, code)
+		assert.Contains(t, stdout, "ID  STATUS")
+		assert.Contains(t, stdout, "First")
+		assert.Contains(t, stdout, "next cursor: abc")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		code, stdout, _ := runTodo("ls", "--status", "new", "-o", "json")
+		assert.Equal(t, exitOK, code)
+
+		var out struct {
+			Tasks      []client.Task `json:"tasks"`
+			NextCursor string        `json:"next_cursor"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+		assert.Len(t, out.Tasks, 2)
+		assert.Equal(t, "abc", out.NextCursor)
+	})
+}
+
+func TestExitCodes(t *testing.T) {
+	isolate(t)
+	srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/tasks/404":
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(dto.Response{Status: "error", Error: &dto.Error{Code: dto.TaskNotFound, Desc: "Task not found"}})
+		case "/v1/tasks/5/transition":
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(dto.Response{Status: "error", Error: &dto.Error{Code: dto.InvalidTransition, Desc: "not allowed"}})
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(dto.Response{Status: "error", Error: &dto.Error{Code: dto.ServiceUnavailable, Desc: dto.InternalError}})
+		}
+	})
+	t.Setenv("TODO_URL", srv.URL)
+	t.Setenv("TODO_TOKEN", "secret")
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+	}{
+		{name: "Задача не найдена", args: []string{"get", "404"}, wantCode: exitNotFound},
+		{name: "Недопустимый переход", args: []string{"done", "5"}, wantCode: exitConflict},
+		{name: "Ошибка сервера", args: []string{"get", "1"}, wantCode: exitUnavailable},
+		{name: "Неверный токен", args: []string{"get", "1", "--token", "wrong"}, wantCode: exitUnauthorized},
+		{name: "id не число", args: []string{"get", "abc"}, wantCode: exitUsage},
+		{name: "Неизвестный флаг", args: []string{"ls", "--unknown"}, wantCode: exitUsage},
+		{name: "Некорректный срок", args: []string{"add", "Task", "--due", "tomorrow"}, wantCode: exitUsage},
+		{name: "Неизвестная команда", args: []string{"rm", "1"}, wantCode: exitUsage},
+		{name: "Сервис недоступен", args: []string{"get", "1", "--url", "http://127.0.0.1:1"}, wantCode: exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runTodo(tt.args...)
+			assert.Equal(t, tt.wantCode, code)
+			assert.NotEmpty(t, stderr)
+		})
+	}
+}
+
+func TestConfigFile(t *testing.T) {
+	isolate(t)
+	srv := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(dto.Response{Status: "success", Data: map[string]any{"task_id": 3, "status": "done"}})
+	})
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"url": "`+srv.URL+`", "token": "wrong", "output": "json"}`), 0o600))
+
+	t.Run("Переменная окружения важнее файла", func(t *testing.T) {
+		t.Setenv("TODO_TOKEN", "secret")
+
+		code, stdout, _ := runTodo("done", "3", "--config", path)
+		assert.Equal(t, exitOK, code)
+		assert.JSONEq(t, `{"task_id": 3, "status": "done"}`, stdout)
+	})
+
+	t.Run("Настройки из файла", func(t *testing.T) {
+		code, _, _ := runTodo("done", "3", "--config", path)
+		assert.Equal(t, exitUnauthorized, code)
+	})
+
+	t.Run("Указанный файл не существует", func(t *testing.T) {
+		code, _, _ := runTodo("done", "3", "--config", filepath.Join(t.TempDir(), "missing.json"))
+		assert.Equal(t, exitError, code)
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"simple-service/pkg/client"
+)
+
+// Вывод результатов таблицей для человека или JSON для скриптов
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printTask - поля задачи по одному на строку
+func printTask(w io.Writer, t *client.Task) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID\t%d\n", t.ID)
+	fmt.Fprintf(tw, "Title\t%s\n", t.Title)
+	fmt.Fprintf(tw, "Description\t%s\n", t.Description)
+	fmt.Fprintf(tw, "Status\t%s\n", t.Status)
+	fmt.Fprintf(tw, "Created\t%s\n", formatTime(t.CreatedAt))
+	fmt.Fprintf(tw, "Updated\t%s\n", formatTime(t.UpdatedAt))
+	fmt.Fprintf(tw, "Due\t%s\n", formatOptionalTime(t.DueAt))
+	fmt.Fprintf(tw, "Remind\t%s\n", formatOptionalTime(t.RemindAt))
+	fmt.Fprintf(tw, "Version\t%d\n", t.Version)
+	return tw.Flush()
+}
+
+// printTasks - таблица задач; если есть следующая страница, под таблицей печатается её курсор
+func printTasks(w io.Writer, tasks []client.Task, nextCursor string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tDUE\tUPDATED\tTITLE")
+	for _, t := range tasks {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", t.ID, t.Status, formatOptionalTime(t.DueAt), formatTime(t.UpdatedAt), t.Title)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if nextCursor != "" {
+		fmt.Fprintf(w, "\nnext cursor: %s\n", nextCursor)
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format(time.DateTime)
+}
+
+// formatOptionalTime - время или прочерк, если оно не задано
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return formatTime(*t)
+}
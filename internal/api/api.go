@@ -1,38 +1,112 @@
 package api
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
 
+	"simple-service/internal/api/handlers"
 	"simple-service/internal/api/middleware"
-	"simple-service/internal/service"
+	"simple-service/internal/auth"
+	"simple-service/internal/config"
+	"simple-service/internal/ratelimit"
 )
 
 // Routers - структура для хранения зависимостей роутов
 type Routers struct {
-	Service service.Service
+	Handlers *handlers.Handlers
+	Health   *Health
+	Log      *zap.SugaredLogger
+	// AccessLog - настройки журнала запросов
+	AccessLog config.AccessLog
+	// RateLimit - ограничение частоты запросов к /v1
+	RateLimit config.RateLimit
+	// IdempotencyStore - хранилище ключей идемпотентности для создания задач
+	IdempotencyStore middleware.IdempotencyStore
+	// IdempotencyTTL - время жизни ключа идемпотентности
+	IdempotencyTTL time.Duration
+	// IdempotencyLockTimeout - через сколько резерв без сохранённого ответа можно занять заново
+	IdempotencyLockTimeout time.Duration
 }
 
 // NewRouters - конструктор для настройки API
-func NewRouters(r *Routers, token string) *fiber.App {
+func NewRouters(r *Routers, authorization fiber.Handler) *fiber.App {
 	app := fiber.New()
 
+	// Метрики HTTP-запросов, подключаются первыми, чтобы учитывать все ответы
+	app.Use(middleware.Metrics())
+
+	// Трассировка запросов, родительский span берётся из traceparent
+	app.Use(middleware.Tracing())
+
+	// Идентификатор запроса и логгер запроса для всех последующих обработчиков
+	app.Use(middleware.RequestID(r.Log))
+
+	// Журнал запросов, одна запись на запрос
+	app.Use(middleware.AccessLog(r.Log, r.AccessLog))
+
 	// Настройка CORS (разрешенные методы, заголовки, авторизация)
 	app.Use(cors.New(cors.Config{
-		AllowMethods:  "GET, POST, PUT, DELETE",
-		AllowHeaders:  "Accept, Authorization, Content-Type, X-CSRF-Token, X-REQUEST-ID",
-		ExposeHeaders: "Link",
+		AllowMethods:  "GET, POST, PUT, PATCH, DELETE",
+		AllowHeaders:  "Accept, Authorization, Content-Type, X-CSRF-Token, X-REQUEST-ID, Idempotency-Key, If-Match",
+		ExposeHeaders: "Link, X-Request-ID, Retry-After, Idempotent-Replayed, ETag",
 		MaxAge:        300,
 	}))
 
+	// Проверки состояния для оркестратора, доступны без авторизации
+	app.Get("/healthz", r.Health.Liveness)
+	app.Get("/readyz", r.Health.Readiness)
+
+	// Метрики для Prometheus
+	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+
 	// Группа маршрутов с авторизацией
-	apiGroup := app.Group("/v1", middleware.Authorization(token))
+	apiGroup := app.Group("/v1", authorization)
+
+	// Права на чтение и изменение задач, проверяются до вызова сервиса
+	read := middleware.Require(r.Log, auth.PermRead)
+	write := middleware.Require(r.Log, auth.PermWrite)
 
-	// Роут для создания задачи
-	apiGroup.Post("/create_task", r.Service.CreateTask)
+	// Лимиты на чтение и изменение считаются по отдельным корзинам
+	readLimit, writeLimit := rateLimits(r.RateLimit)
+
+	// Роут для создания задачи, повтор с тем же Idempotency-Key не создаёт дубль
+	idempotency := middleware.Idempotency(r.IdempotencyStore, r.IdempotencyTTL, r.IdempotencyLockTimeout, r.Log)
+	apiGroup.Post("/create_task", writeLimit, write, idempotency, r.Handlers.CreateTask)
+
+	// Роут для получения списка задач
+	apiGroup.Get("/tasks", readLimit, read, r.Handlers.ListTasks)
+
+	// Роут для полнотекстового поиска, регистрируется раньше /tasks/:id
+	apiGroup.Get("/tasks/search", readLimit, read, r.Handlers.SearchTasks)
 
 	// Роут для получения задачи по id
-	apiGroup.Get("/tasks/:id", r.Service.GetTask)
+	apiGroup.Get("/tasks/:id", readLimit, read, r.Handlers.GetTask)
+
+	// Роуты для полного и частичного обновления задачи
+	apiGroup.Put("/tasks/:id", writeLimit, write, r.Handlers.UpdateTask)
+	apiGroup.Patch("/tasks/:id", writeLimit, write, r.Handlers.PatchTask)
+
+	// Роут для удаления задачи, администратор может удалить и чужую задачу
+	apiGroup.Delete("/tasks/:id", writeLimit, write, r.Handlers.DeleteTask)
+
+	// Роут для смены статуса задачи
+	apiGroup.Post("/tasks/:id/transition", writeLimit, write, r.Handlers.TransitionTask)
 
 	return app
 }
+
+// rateLimits - миддлваеры ограничения частоты запросов на чтение и изменение.
+// Если ограничение выключено, запросы пропускаются без проверки
+func rateLimits(cfg config.RateLimit) (read, write fiber.Handler) {
+	if !cfg.Enabled {
+		next := func(c *fiber.Ctx) error { return c.Next() }
+		return next, next
+	}
+	return middleware.RateLimit(ratelimit.NewLimiter(cfg.ReadRate, cfg.ReadBurst)),
+		middleware.RateLimit(ratelimit.NewLimiter(cfg.WriteRate, cfg.WriteBurst))
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"simple-service/internal/dto"
+)
+
+// Оптимистичная блокировка: клиент получает версию задачи в ETag
+// и передаёт её в If-Match при изменении или удалении
+
+var (
+	errIfMatchRequired = errors.New("If-Match header is required")
+	errIfMatchInvalid  = errors.New("If-Match header must be a task ETag or *")
+)
+
+// etag - значение ETag для версии задачи
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// ifMatch - ожидаемая версия задачи из заголовка If-Match.
+// Для "*" возвращается nil: подходит любая версия
+func ifMatch(ctx *fiber.Ctx) (*int, error) {
+	value := strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch))
+	if value == "" {
+		return nil, errIfMatchRequired
+	}
+	if value == "*" {
+		return nil, nil
+	}
+
+	// If-Match сравнивает только сильные ETag, поэтому W/"1" не подходит
+	unquoted, err := strconv.Unquote(value)
+	if err != nil || !strings.HasPrefix(value, `"`) {
+		return nil, errIfMatchInvalid
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil {
+		return nil, errIfMatchInvalid
+	}
+	return &version, nil
+}
+
+// preconditionError - ответ на отсутствующий или некорректный If-Match.
+// 412 VERSION_MISMATCH остаётся только для настоящего расхождения версий
+func preconditionError(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, errIfMatchRequired) {
+		return dto.PreconditionRequiredError(ctx, dto.PreconditionNeeded, err.Error())
+	}
+	return dto.BadResponseError(ctx, dto.FieldBadFormat, err.Error())
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		wantVersion *int
+		wantErr     error
+	}{
+		{name: "Версия из ETag", header: `"7"`, wantVersion: intPtr(7)},
+		{name: "Любая версия", header: "*"},
+		{name: "Заголовка нет", wantErr: errIfMatchRequired},
+		{name: "Слабый ETag не подходит", header: `W/"7"`, wantErr: errIfMatchInvalid},
+		{name: "ETag без кавычек", header: "7", wantErr: errIfMatchInvalid},
+		{name: "ETag не число", header: `"abc"`, wantErr: errIfMatchInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Put("/", func(c *fiber.Ctx) error {
+				version, err := ifMatch(c)
+				assert.Equal(t, tt.wantVersion, version)
+				assert.Equal(t, tt.wantErr, err)
+				return nil
+			})
+
+			req, _ := http.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(fiber.HeaderIfMatch, tt.header)
+			}
+			_, err := app.Test(req)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	assert.Equal(t, `"12"`, etag(12))
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/dto"
+	logging "simple-service/internal/logger"
+	"simple-service/internal/service"
+	"simple-service/internal/tracing"
+)
+
+// HTTP-обработчики задач. Здесь только разбор запроса и формирование ответа,
+// бизнес-логика находится в service.TaskService
+
+// Handlers - обработчики маршрутов /v1 поверх сервиса задач
+type Handlers struct {
+	tasks service.TaskService
+	log   *zap.SugaredLogger
+}
+
+// New - конструктор обработчиков
+func New(tasks service.TaskService, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{tasks: tasks, log: logger}
+}
+
+// CreateTask - обработчик запроса на создание задачи
+func (h *Handlers) CreateTask(ctx *fiber.Ctx) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return dto.UnauthorizedError(ctx, dto.Unauthorized, "Unauthorized")
+	}
+
+	var req service.TaskRequest
+
+	// Десериализация JSON-запроса
+	if err := json.Unmarshal(ctx.Body(), &req); err != nil {
+		h.logger(ctx).Error("Invalid request body", zap.Error(err))
+		return dto.BadResponseError(ctx, dto.FieldBadFormat, "Invalid request body")
+	}
+
+	taskID, err := h.tasks.CreateTask(ctx.UserContext(), principal, req)
+	if err != nil {
+		return h.fail(ctx, err, "Failed to insert task")
+	}
+
+	// Формирование ответа
+	response := dto.Response{
+		Status: "success",
+		Data:   map[string]int{"task_id": taskID},
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetTask - обработчик запроса на получение задачи по id
+func (h *Handlers) GetTask(ctx *fiber.Ctx) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return dto.UnauthorizedError(ctx, dto.Unauthorized, "Unauthorized")
+	}
+
+	taskID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		h.logger(ctx).Error("Failed to parse int", zap.Error(err))
+		return dto.BadResponseError(ctx, dto.FieldBadFormat, "ID must be only number")
+	}
+
+	task, err := h.tasks.GetTask(ctx.UserContext(), principal, taskID)
+	if err != nil {
+		return h.fail(ctx, err, "Failed to get task")
+	}
+	ctx.Set(fiber.HeaderETag, etag(task.Version))
+
+	// Формирование ответа
+	response := dto.Response{
+		Status: "success",
+		Data:   task,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// ListTasks - обработчик запроса на получение списка задач
+func (h *Handlers) ListTasks(ctx *fiber.Ctx) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return dto.UnauthorizedError(ctx, dto.Unauthorized, "Unauthorized")
+	}
+
+	var req service.ListTasksRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		h.logger(ctx).Error("Invalid query parameters", zap.Error(err))
+		return dto.BadResponseError(ctx, dto.FieldBadFormat, "Invalid query parameters")
+	}
+
+	list, err := h.tasks.ListTasks(ctx.UserContext(), principal, req)
+	if err != nil {
+		return h.fail(ctx, err, "Failed to list tasks")
+	}
+
+	response := dto.Response{
+		Status:     "success",
+		Data:       list.Tasks,
+		NextCursor: list.NextCursor,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// SearchTasks - обработчик запроса полнотекстового поиска задач
+func (h *Handlers) SearchTasks(ctx *fiber.Ctx) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return dto.UnauthorizedError(ctx, dto.Unauthorized, "Unauthorized")
+	}
+
+	var req service.SearchTasksRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		h.logger(ctx).Error("Invalid query parameters", zap.Error(err))
+		return dto.BadResponseError(ctx, dto.FieldBadFormat, "Invalid query parameters")
+	}
+
+	results, err := h.tasks.SearchTasks(ctx.UserContext(), principal, req)
+	if err != nil {
+		return h.fail(ctx, err, "Failed to search tasks")
+	}
+
+	response := dto.Response{
+		Status: "success",
+		Data:   results,
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// UpdateTask - обработчик запроса на полное обновление задачи
+func (h *Handlers) UpdateTask(ctx *fiber.Ctx) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return dto.UnauthorizedError(ctx, dto.Unauthorized, "Unauthorized")
+	}
+
+	taskID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		h.logger(ctx).Error("Failed to parse int", zap.Error(err))
+		return dto.BadResponseError(ctx, dto.FieldBadFormat, "ID must be only number")
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return preconditionError(ctx, err)
+	}
+
+	var req service.TaskRequest
+	if err := json.Unmarshal(ctx.Body(), &req); err != nil {
+		h.logger(ctx).Error("Invalid request body", zap.Error(err))
+		return dto.BadResponseError(ctx, dto.FieldBadFormat, "Invalid request body")
+	}
+
+	newVersion, err := h.tasks.UpdateTask(ctx.UserContext(), principal, taskID, req, version)
+	if err != nil {
+		return h.fail(ctx, err, "Failed to update task")
+	}
+	ctx.Set(fiber.HeaderETag, etag(newVersion))
+
+	response := dto.Response{
+		Status: "success",
+		Data:   map[string]int{"task_id": taskID, "version": newVersion},
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// PatchTask - обработчик запроса на частичное обновление задачи
+func (h *Handlers) PatchTask(ctx *fiber.Ctx) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return dto.UnauthorizedError(ctx, dto.Unauthorized, "Unauthorized")
+	}
+
+	taskID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		h.logger(ctx).Error("Failed to parse int", zap.Error(err))
+		return dto.BadResponseError(ctx, dto.FieldBadFormat, "ID must be only number")
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return preconditionError(ctx, err)
+	}
+
+	var req service.TaskPatchRequest
+	if err := json.Unmarshal(ctx.Body(), &req); err != nil {
+		h.logger(ctx).Error("Invalid request body", zap.Error(err))
+		return dto.BadResponseError(ctx, dto.FieldBadFormat, "Invalid request body")
+	}
+
+	newVersion, err := h.tasks.PatchTask(ctx.UserContext(), principal, taskID, req, version)
+	if err != nil {
+		return h.fail(ctx, err, "Failed to patch task")
+	}
+	ctx.Set(fiber.HeaderETag, etag(newVersion))
+
+	response := dto.Response{
+		Status: "success",
+		Data:   map[string]int{"task_id": taskID, "version": newVersion},
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteTask - обработчик запроса на удаление задачи
+func (h *Handlers) DeleteTask(ctx *fiber.Ctx) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return dto.UnauthorizedError(ctx, dto.Unauthorized, "Unauthorized")
+	}
+
+	taskID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		h.logger(ctx).Error("Failed to parse int", zap.Error(err))
+		return dto.BadResponseError(ctx, dto.FieldBadFormat, "ID must be only number")
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return preconditionError(ctx, err)
+	}
+
+	if err := h.tasks.DeleteTask(ctx.UserContext(), principal, taskID, version); err != nil {
+		return h.fail(ctx, err, "Failed to delete task")
+	}
+
+	response := dto.Response{
+		Status: "success",
+		Data:   map[string]int{"task_id": taskID},
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// TransitionTask - обработчик запроса на смену статуса задачи
+func (h *Handlers) TransitionTask(ctx *fiber.Ctx) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return dto.UnauthorizedError(ctx, dto.Unauthorized, "Unauthorized")
+	}
+
+	taskID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		h.logger(ctx).Error("Failed to parse int", zap.Error(err))
+		return dto.BadResponseError(ctx, dto.FieldBadFormat, "ID must be only number")
+	}
+
+	var req service.TransitionRequest
+	if err := json.Unmarshal(ctx.Body(), &req); err != nil {
+		h.logger(ctx).Error("Invalid request body", zap.Error(err))
+		return dto.BadResponseError(ctx, dto.FieldBadFormat, "Invalid request body")
+	}
+
+	status, err := h.tasks.TransitionTask(ctx.UserContext(), principal, taskID, req)
+	if err != nil {
+		return h.fail(ctx, err, "Failed to update task status")
+	}
+
+	response := dto.Response{
+		Status: "success",
+		Data: map[string]any{
+			"task_id": taskID,
+			"status":  status,
+		},
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// fail - ответ на ошибку сервиса. Доменные ошибки превращаются в ответы с кодом dto,
+// остальные записываются в лог с сообщением msg и скрываются за 500
+func (h *Handlers) fail(ctx *fiber.Ctx, err error, msg string) error {
+	var validationErr *service.ValidationError
+	var transitionErr *service.TransitionError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return dto.BadResponseError(ctx, dto.FieldIncorrect, validationErr.Error())
+	case errors.Is(err, service.ErrUnauthenticated):
+		return dto.UnauthorizedError(ctx, dto.Unauthorized, "Unauthorized")
+	case errors.Is(err, service.ErrTaskNotFound):
+		return dto.NotFoundError(ctx, dto.TaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrVersionMismatch):
+		return dto.PreconditionFailedError(ctx, dto.VersionMismatch, "Task was modified, reload it and retry")
+	case errors.As(err, &transitionErr):
+		return dto.ConflictError(ctx, dto.InvalidTransition, transitionErr.Error())
+	case errors.Is(err, service.ErrStatusChanged):
+		return dto.ConflictError(ctx, dto.InvalidTransition, service.ErrStatusChanged.Error())
+	}
+
+	h.logger(ctx).Error(msg, zap.Error(err))
+	return dto.InternalServerError(ctx)
+}
+
+// logger - логгер запроса, в нём уже есть trace_id и span_id. Если миддлваер логгера запроса
+// не подключён, используется общий логгер с полями текущего span
+func (h *Handlers) logger(ctx *fiber.Ctx) *zap.SugaredLogger {
+	if log := logging.FromContext(ctx, nil); log != nil {
+		return log
+	}
+	return h.log.With(tracing.LogFields(ctx.UserContext())...)
+}
@@ -0,0 +1,853 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/dto"
+	logging "simple-service/internal/logger"
+	"simple-service/internal/repo"
+	"simple-service/internal/repo/mocks"
+	"simple-service/internal/service"
+)
+
+const testOwner = "user"
+
+// intPtr - указатель на значение для ожидаемой версии задачи
+func intPtr(v int) *int {
+	return &v
+}
+
+// newTestApp - Fiber-приложение, в котором все запросы выполняются от имени testOwner
+func newTestApp() *fiber.App {
+	return newTestAppWithRoles(auth.RoleEditor)
+}
+
+// newTestAppWithRoles - Fiber-приложение с пользователем testOwner и указанными ролями
+func newTestAppWithRoles(roles ...string) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		auth.SetPrincipal(c, auth.Principal{Subject: testOwner, Roles: roles})
+		return c.Next()
+	})
+	return app
+}
+
+// TestCreateTask - тестирование метода CreateTask
+func TestCreateTask(t *testing.T) {
+	// Создаем мок репозитория
+	mockRepo := new(mocks.Repository)
+	logger := zap.NewNop().Sugar() // Без вывода логов
+
+	// Создаем обработчики поверх сервиса с мок-репозиторием
+	h := New(service.NewTaskService(mockRepo), logger)
+
+	// Инициализируем Fiber-контекст
+	app := newTestApp()
+	app.Post("/tasks", h.CreateTask)
+
+	t.Run("успешное создание задачи", func(t *testing.T) {
+		task := service.TaskRequest{
+			Title:       "Test Task",
+			Description: "Test Description",
+		}
+		body, _ := json.Marshal(task)
+
+		// Ожидаем, что вызов метода `CreateTask` в репозитории вернёт ID = 1
+		mockRepo.On("CreateTask", mock.Anything, testOwner, repo.Task{
+			Title:       task.Title,
+			Description: task.Description,
+		}).Return(1, nil).Once()
+
+		// Отправляем запрос
+		req, err := http.NewRequest("POST", "/tasks", bytes.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		// Выполняем запрос
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		// Проверяем ответ
+		var response dto.Response
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.Equal(t, "success", response.Status)
+
+		// Проверяем вызов мок-методов
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ошибка валидации входных данных", func(t *testing.T) {
+		body := []byte(`{}`) // Пустое тело, `title` обязателен
+
+		req, err := http.NewRequest("POST", "/tasks", bytes.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		var response dto.Response
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.Equal(t, "error", response.Status)
+	})
+
+	t.Run("напоминание позже срока", func(t *testing.T) {
+		body := []byte(`{"title": "Task", "due_at": "2026-01-02T15:00:00Z", "remind_at": "2026-01-02T16:00:00Z"}`)
+
+		req, err := http.NewRequest("POST", "/tasks", bytes.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		var response dto.Response
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.Equal(t, dto.FieldIncorrect, response.Error.Code)
+	})
+
+	t.Run("ошибка при создании задачи в БД", func(t *testing.T) {
+		task := service.TaskRequest{
+			Title:       "Test Task",
+			Description: "Test Description",
+		}
+		body, _ := json.Marshal(task)
+
+		// Ожидаем ошибку при вставке в БД
+		mockRepo.On("CreateTask", mock.Anything, testOwner, repo.Task{
+			Title:       task.Title,
+			Description: task.Description,
+		}).Return(0, errors.New("DB error")).Once()
+
+		req, err := http.NewRequest("POST", "/tasks", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+		var response dto.Response
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.Equal(t, "error", response.Status)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestGetTask - тестирование метода GetTask
+func TestGetTask(t *testing.T) {
+	mockRepo := new(mocks.Repository)
+	logger := zap.NewNop().Sugar()
+
+	h := New(service.NewTaskService(mockRepo), logger)
+
+	app := newTestApp()
+	app.Get("/tasks/:id", h.GetTask)
+
+	t.Run("успешное получение задачи", func(t *testing.T) {
+		createdAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+		task := &repo.Task{
+			ID:          1,
+			Title:       "Test Task",
+			Description: "Test Description",
+			Status:      repo.StatusNew,
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt.Add(time.Hour),
+			Version:     3,
+		}
+		mockRepo.On("GetTask", mock.Anything, testOwner, 1).Return(task, nil).Once()
+
+		req, err := http.NewRequest("GET", "/tasks/1", nil)
+		assert.NoError(t, err)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, `"3"`, resp.Header.Get("ETag"))
+
+		var response struct {
+			Status string    `json:"status"`
+			Data   repo.Task `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.Equal(t, "success", response.Status)
+		assert.Equal(t, *task, response.Data)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("задача не найдена", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 2).Return(nil, repo.ErrTaskNotFound).Once()
+
+		req, err := http.NewRequest("GET", "/tasks/2", nil)
+		assert.NoError(t, err)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+		var response dto.Response
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.Equal(t, dto.TaskNotFound, response.Error.Code)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ошибка при получении задачи из БД", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 3).Return(nil, errors.New("DB error")).Once()
+
+		req, err := http.NewRequest("GET", "/tasks/3", nil)
+		assert.NoError(t, err)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("некорректный id", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/tasks/abc", nil)
+		assert.NoError(t, err)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+// TestListTasks - тестирование метода ListTasks
+func TestListTasks(t *testing.T) {
+	mockRepo := new(mocks.Repository)
+	logger := zap.NewNop().Sugar()
+
+	h := New(service.NewTaskService(mockRepo), logger)
+
+	app := newTestApp()
+	app.Get("/tasks", h.ListTasks)
+
+	t.Run("успешное получение списка задач", func(t *testing.T) {
+		mockRepo.On("ListTasks", mock.Anything, testOwner, repo.TaskFilter{
+			Sort:  repo.SortCreatedAt,
+			Limit: 20,
+		}).Return(&repo.TaskPage{Tasks: []repo.Task{
+			{ID: 1, Title: "First", Description: "First Description"},
+			{ID: 2, Title: "Second"},
+		}}, nil).Once()
+
+		req, err := http.NewRequest("GET", "/tasks", nil)
+		assert.NoError(t, err)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var response struct {
+			Status     string      `json:"status"`
+			Data       []repo.Task `json:"data"`
+			NextCursor string      `json:"next_cursor"`
+		}
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.Equal(t, "success", response.Status)
+		assert.Len(t, response.Data, 2)
+		assert.Empty(t, response.NextCursor)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("переход на следующую страницу", func(t *testing.T) {
+		status := repo.StatusNew
+		next := repo.TaskCursor{ID: 2, Title: "b"}
+
+		mockRepo.On("ListTasks", mock.Anything, testOwner, repo.TaskFilter{
+			Status: &status,
+			Sort:   repo.SortTitle,
+			Desc:   true,
+			Limit:  2,
+		}).Return(&repo.TaskPage{
+			Tasks: []repo.Task{{ID: 1, Title: "c"}, {ID: 2, Title: "b"}},
+			Next:  &next,
+		}, nil).Once()
+
+		req, err := http.NewRequest("GET", "/tasks?limit=2&sort=-title&status=new", nil)
+		assert.NoError(t, err)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var response dto.Response
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.NotEmpty(t, response.NextCursor)
+
+		// Курсор из ответа передаётся в репозиторий как позиция следующей страницы
+		mockRepo.On("ListTasks", mock.Anything, testOwner, repo.TaskFilter{
+			Status: &status,
+			Sort:   repo.SortTitle,
+			Desc:   true,
+			After:  &next,
+			Limit:  2,
+		}).Return(&repo.TaskPage{Tasks: []repo.Task{{ID: 3, Title: "a"}}}, nil).Once()
+
+		req, err = http.NewRequest("GET", "/tasks?limit=2&sort=-title&status=new&cursor="+response.NextCursor, nil)
+		assert.NoError(t, err)
+
+		resp, err = app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("просроченные задачи", func(t *testing.T) {
+		mockRepo.On("ListTasks", mock.Anything, testOwner, repo.TaskFilter{Overdue: true, Sort: repo.SortCreatedAt, Limit: 20}).
+			Return(&repo.TaskPage{}, nil).Once()
+
+		req, err := http.NewRequest("GET", "/tasks?overdue=true", nil)
+		assert.NoError(t, err)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("курсор от другой сортировки", func(t *testing.T) {
+		// Курсор выдан для сортировки по возрастанию заголовка
+		mockRepo.On("ListTasks", mock.Anything, testOwner, repo.TaskFilter{Sort: repo.SortTitle, Limit: 20}).
+			Return(&repo.TaskPage{Tasks: []repo.Task{{ID: 2, Title: "b"}}, Next: &repo.TaskCursor{ID: 2, Title: "b"}}, nil).Once()
+
+		req, err := http.NewRequest("GET", "/tasks?sort=title", nil)
+		assert.NoError(t, err)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var page dto.Response
+		json.NewDecoder(resp.Body).Decode(&page)
+		cursor := page.NextCursor
+		assert.NotEmpty(t, cursor)
+
+		req, err = http.NewRequest("GET", "/tasks?sort=-title&cursor="+cursor, nil)
+		assert.NoError(t, err)
+
+		resp, err = app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("некорректные параметры", func(t *testing.T) {
+		for _, query := range []string{
+			"limit=0x", "limit=101", "limit=-1", "sort=id", "status=archived", "created_from=2025-01-01", "cursor=%25%25",
+		} {
+			req, err := http.NewRequest("GET", "/tasks?"+query, nil)
+			assert.NoError(t, err)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, query)
+		}
+	})
+
+	t.Run("запрос без пользователя", func(t *testing.T) {
+		anonymous := fiber.New()
+		anonymous.Get("/tasks", h.ListTasks)
+
+		req, err := http.NewRequest("GET", "/tasks", nil)
+		assert.NoError(t, err)
+
+		resp, err := anonymous.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("ошибка при получении списка из БД", func(t *testing.T) {
+		mockRepo.On("ListTasks", mock.Anything, testOwner, mock.Anything).Return(nil, errors.New("DB error")).Once()
+
+		req, err := http.NewRequest("GET", "/tasks", nil)
+		assert.NoError(t, err)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestSearchTasks - тестирование метода SearchTasks
+func TestSearchTasks(t *testing.T) {
+	mockRepo := new(mocks.Repository)
+	logger := zap.NewNop().Sugar()
+
+	h := New(service.NewTaskService(mockRepo), logger)
+
+	app := newTestApp()
+	app.Get("/tasks/search", h.SearchTasks)
+
+	t.Run("поиск с языком по умолчанию", func(t *testing.T) {
+		mockRepo.On("SearchTasks", mock.Anything, testOwner, "отчёт", repo.LanguageRussian, 20).
+			Return([]repo.TaskSearchResult{{
+				Task:      repo.Task{ID: 1, Title: "Квартальный отчёт"},
+				Rank:      0.6,
+				Highlight: repo.TaskHighlight{Title: "Квартальный <b>отчёт</b>"},
+			}}, nil).Once()
+
+		req, err := http.NewRequest("GET", "/tasks/search?q=%D0%BE%D1%82%D1%87%D1%91%D1%82", nil)
+		assert.NoError(t, err)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var response struct {
+			Status string                  `json:"status"`
+			Data   []repo.TaskSearchResult `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.Len(t, response.Data, 1)
+		assert.Equal(t, 1, response.Data[0].ID)
+		assert.Equal(t, "Квартальный <b>отчёт</b>", response.Data[0].Highlight.Title)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("поиск на английском", func(t *testing.T) {
+		mockRepo.On("SearchTasks", mock.Anything, testOwner, "reports", repo.LanguageEnglish, 5).
+			Return([]repo.TaskSearchResult{}, nil).Once()
+
+		req, err := http.NewRequest("GET", "/tasks/search?q=reports&lang=en&limit=5", nil)
+		assert.NoError(t, err)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("некорректные параметры", func(t *testing.T) {
+		for _, query := range []string{"", "q=test&lang=de", "q=test&limit=1000"} {
+			req, err := http.NewRequest("GET", "/tasks/search?"+query, nil)
+			assert.NoError(t, err)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, query)
+		}
+	})
+}
+
+// TestUpdateTask - тестирование метода UpdateTask
+func TestUpdateTask(t *testing.T) {
+	mockRepo := new(mocks.Repository)
+	logger := zap.NewNop().Sugar()
+
+	h := New(service.NewTaskService(mockRepo), logger)
+
+	app := newTestApp()
+	app.Put("/tasks/:id", h.UpdateTask)
+
+	t.Run("успешное обновление задачи", func(t *testing.T) {
+		task := service.TaskRequest{
+			Title:       "Updated Task",
+			Description: "Updated Description",
+		}
+		body, _ := json.Marshal(task)
+
+		mockRepo.On("UpdateTask", mock.Anything, testOwner, 1, repo.Task{
+			Title:       task.Title,
+			Description: task.Description,
+		}, intPtr(3)).Return(4, nil).Once()
+
+		req, err := http.NewRequest("PUT", "/tasks/1", bytes.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"3"`)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, `"4"`, resp.Header.Get("ETag"))
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("задача не найдена", func(t *testing.T) {
+		mockRepo.On("UpdateTask", mock.Anything, testOwner, 2, repo.Task{Title: "Task"}, (*int)(nil)).
+			Return(0, repo.ErrTaskNotFound).Once()
+
+		req, err := http.NewRequest("PUT", "/tasks/2", bytes.NewReader([]byte(`{"title":"Task"}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", "*")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("некорректный id", func(t *testing.T) {
+		req, err := http.NewRequest("PUT", "/tasks/abc", bytes.NewReader([]byte(`{"title":"Task"}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("ошибка валидации входных данных", func(t *testing.T) {
+		req, err := http.NewRequest("PUT", "/tasks/1", bytes.NewReader([]byte(`{}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("некорректный If-Match", func(t *testing.T) {
+		for _, header := range []string{"7", `W/"7"`, `"abc"`} {
+			req, err := http.NewRequest("PUT", "/tasks/1", bytes.NewReader([]byte(`{"title":"Task"}`)))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", header)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, header)
+
+			var response dto.Response
+			json.NewDecoder(resp.Body).Decode(&response)
+			assert.Equal(t, dto.FieldBadFormat, response.Error.Code, header)
+		}
+	})
+
+	t.Run("версия задачи изменилась", func(t *testing.T) {
+		mockRepo.On("UpdateTask", mock.Anything, testOwner, 1, repo.Task{Title: "Task"}, intPtr(1)).
+			Return(0, repo.ErrVersionMismatch).Once()
+
+		req, err := http.NewRequest("PUT", "/tasks/1", bytes.NewReader([]byte(`{"title":"Task"}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusPreconditionFailed, resp.StatusCode)
+
+		var response dto.Response
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.Equal(t, dto.VersionMismatch, response.Error.Code)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("без If-Match", func(t *testing.T) {
+		req, err := http.NewRequest("PUT", "/tasks/1", bytes.NewReader([]byte(`{"title":"Task"}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusPreconditionRequired, resp.StatusCode)
+
+		var response dto.Response
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.Equal(t, dto.PreconditionNeeded, response.Error.Code)
+	})
+}
+
+// TestPatchTask - тестирование метода PatchTask
+func TestPatchTask(t *testing.T) {
+	mockRepo := new(mocks.Repository)
+	logger := zap.NewNop().Sugar()
+
+	h := New(service.NewTaskService(mockRepo), logger)
+
+	app := newTestApp()
+	app.Patch("/tasks/:id", h.PatchTask)
+
+	t.Run("обновление только заголовка", func(t *testing.T) {
+		title := "Patched Task"
+
+		mockRepo.On("PatchTask", mock.Anything, testOwner, 1, repo.TaskPatch{
+			Title: &title,
+		}, intPtr(1)).Return(2, nil).Once()
+
+		req, err := http.NewRequest("PATCH", "/tasks/1", bytes.NewReader([]byte(`{"title":"Patched Task"}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("null убирает срок и напоминание", func(t *testing.T) {
+		mockRepo.On("PatchTask", mock.Anything, testOwner, 1, repo.TaskPatch{
+			DueAt:    repo.NullableTime{Set: true},
+			RemindAt: repo.NullableTime{Set: true},
+		}, intPtr(2)).Return(3, nil).Once()
+
+		req, err := http.NewRequest("PATCH", "/tasks/1", bytes.NewReader([]byte(`{"due_at":null,"remind_at":null}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"2"`)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("задача не найдена", func(t *testing.T) {
+		description := "Description"
+
+		mockRepo.On("PatchTask", mock.Anything, testOwner, 2, repo.TaskPatch{
+			Description: &description,
+		}, intPtr(1)).Return(0, repo.ErrTaskNotFound).Once()
+
+		req, err := http.NewRequest("PATCH", "/tasks/2", bytes.NewReader([]byte(`{"description":"Description"}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("пустой заголовок", func(t *testing.T) {
+		req, err := http.NewRequest("PATCH", "/tasks/1", bytes.NewReader([]byte(`{"title":""}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+// TestDeleteTask - тестирование метода DeleteTask
+func TestDeleteTask(t *testing.T) {
+	mockRepo := new(mocks.Repository)
+	logger := zap.NewNop().Sugar()
+
+	h := New(service.NewTaskService(mockRepo), logger)
+
+	app := newTestApp()
+	app.Delete("/tasks/:id", h.DeleteTask)
+
+	t.Run("успешное удаление задачи", func(t *testing.T) {
+		mockRepo.On("DeleteTask", mock.Anything, testOwner, 1, intPtr(2)).Return(nil).Once()
+
+		req, err := http.NewRequest("DELETE", "/tasks/1", nil)
+		assert.NoError(t, err)
+		req.Header.Set("If-Match", `"2"`)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("задача не найдена", func(t *testing.T) {
+		mockRepo.On("DeleteTask", mock.Anything, testOwner, 3, (*int)(nil)).Return(repo.ErrTaskNotFound).Once()
+
+		req, err := http.NewRequest("DELETE", "/tasks/3", nil)
+		assert.NoError(t, err)
+		req.Header.Set("If-Match", "*")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("администратор удаляет чужую задачу", func(t *testing.T) {
+		admin := newTestAppWithRoles(auth.RoleAdmin)
+		admin.Delete("/tasks/:id", h.DeleteTask)
+
+		mockRepo.On("DeleteAnyTask", mock.Anything, 4, intPtr(1)).Return(nil).Once()
+
+		req, err := http.NewRequest("DELETE", "/tasks/4", nil)
+		assert.NoError(t, err)
+		req.Header.Set("If-Match", `"1"`)
+
+		resp, err := admin.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ошибка при удалении задачи в БД", func(t *testing.T) {
+		mockRepo.On("DeleteTask", mock.Anything, testOwner, 2, intPtr(1)).Return(errors.New("DB error")).Once()
+
+		req, err := http.NewRequest("DELETE", "/tasks/2", nil)
+		assert.NoError(t, err)
+		req.Header.Set("If-Match", `"1"`)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("версия задачи изменилась", func(t *testing.T) {
+		mockRepo.On("DeleteTask", mock.Anything, testOwner, 5, intPtr(1)).Return(repo.ErrVersionMismatch).Once()
+
+		req, err := http.NewRequest("DELETE", "/tasks/5", nil)
+		assert.NoError(t, err)
+		req.Header.Set("If-Match", `"1"`)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusPreconditionFailed, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestTransitionTask - тестирование метода TransitionTask
+func TestTransitionTask(t *testing.T) {
+	mockRepo := new(mocks.Repository)
+	logger := zap.NewNop().Sugar()
+
+	h := New(service.NewTaskService(mockRepo), logger)
+
+	app := newTestApp()
+	app.Post("/tasks/:id/transition", h.TransitionTask)
+
+	t.Run("успешная смена статуса", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 1).Return(&repo.Task{Title: "Task", Status: repo.StatusNew}, nil).Once()
+		mockRepo.On("UpdateTaskStatus", mock.Anything, testOwner, 1, repo.StatusNew, repo.StatusInProgress).Return(nil).Once()
+
+		req, err := http.NewRequest("POST", "/tasks/1/transition", bytes.NewReader([]byte(`{"status":"in_progress"}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("недопустимый переход", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 2).Return(&repo.Task{Title: "Task", Status: repo.StatusDone}, nil).Once()
+
+		req, err := http.NewRequest("POST", "/tasks/2/transition", bytes.NewReader([]byte(`{"status":"new"}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+
+		var response dto.Response
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.Equal(t, dto.InvalidTransition, response.Error.Code)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("задача не найдена", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 3).Return(nil, repo.ErrTaskNotFound).Once()
+
+		req, err := http.NewRequest("POST", "/tasks/3/transition", bytes.NewReader([]byte(`{"status":"done"}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("статус изменён параллельно", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 4).Return(&repo.Task{Title: "Task", Status: repo.StatusNew}, nil).Once()
+		mockRepo.On("UpdateTaskStatus", mock.Anything, testOwner, 4, repo.StatusNew, repo.StatusDone).
+			Return(repo.ErrStatusChanged).Once()
+
+		req, err := http.NewRequest("POST", "/tasks/4/transition", bytes.NewReader([]byte(`{"status":"done"}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("неизвестный статус", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/tasks/1/transition", bytes.NewReader([]byte(`{"status":"archived"}`)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+// TestRequestLogger - ошибки пишутся в логгер запроса, а не в общий логгер
+func TestRequestLogger(t *testing.T) {
+	mockRepo := new(mocks.Repository)
+	serviceCore, serviceLogs := observer.New(zap.InfoLevel)
+	requestCore, requestLogs := observer.New(zap.InfoLevel)
+	h := New(service.NewTaskService(mockRepo), zap.New(serviceCore).Sugar())
+
+	app := newTestApp()
+	app.Use(func(c *fiber.Ctx) error {
+		logging.SetRequestLogger(c, zap.New(requestCore).Sugar().With("request_id", "req-1"))
+		return c.Next()
+	})
+	app.Get("/tasks/:id", h.GetTask)
+
+	mockRepo.On("GetTask", mock.Anything, testOwner, 1).Return(nil, errors.New("db error")).Once()
+
+	req, _ := http.NewRequest("GET", "/tasks/1", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	assert.Zero(t, serviceLogs.Len())
+	entries := requestLogs.TakeAll()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "req-1", entries[0].ContextMap()["request_id"])
+	}
+	mockRepo.AssertExpectations(t)
+}
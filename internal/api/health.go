@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"simple-service/internal/dto"
+)
+
+// Проверки состояния сервиса для оркестратора и балансировщика
+
+const (
+	shuttingDown   = "SHUTTING_DOWN"
+	checkTimeout   = 2 * time.Second
+	databaseFailed = "Database is unavailable"
+)
+
+// Checker - зависимости, без которых сервис не может обслуживать запросы
+type Checker interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (int, error)
+}
+
+// Health - обработчики /healthz и /readyz
+type Health struct {
+	checker  Checker
+	log      *zap.SugaredLogger
+	draining atomic.Bool
+	// versionFailed - последняя попытка получить версию миграций завершилась ошибкой.
+	// Проверка выполняется на каждый запрос оркестратора, поэтому в лог пишется только смена состояния
+	versionFailed atomic.Bool
+}
+
+// NewHealth - конструктор проверок состояния
+func NewHealth(checker Checker, logger *zap.SugaredLogger) *Health {
+	return &Health{checker: checker, log: logger}
+}
+
+// Drain - перевод readiness в состояние ошибки перед остановкой,
+// чтобы балансировщик перестал направлять новые запросы
+func (h *Health) Drain() {
+	h.draining.Store(true)
+}
+
+// Liveness - процесс жив и обрабатывает запросы
+func (h *Health) Liveness(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusOK).JSON(dto.Response{Status: "success"})
+}
+
+// Readiness - сервис готов принимать трафик: не останавливается и БД доступна
+func (h *Health) Readiness(ctx *fiber.Ctx) error {
+	if h.draining.Load() {
+		return dto.UnavailableError(ctx, shuttingDown, "Service is shutting down")
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx.UserContext(), checkTimeout)
+	defer cancel()
+
+	if err := h.checker.Ping(checkCtx); err != nil {
+		h.log.Errorw("Readiness check failed", "error", err)
+		return dto.UnavailableError(ctx, dto.ServiceUnavailable, databaseFailed)
+	}
+
+	data := fiber.Map{"database": "ok"}
+	// Версия миграций справочная: её отсутствие не делает сервис неготовым
+	if version, err := h.checker.MigrationVersion(checkCtx); err == nil {
+		data["migration_version"] = version
+		if h.versionFailed.Swap(false) {
+			h.log.Infow("Migration version is available again", "migration_version", version)
+		}
+	} else if !h.versionFailed.Swap(true) {
+		h.log.Warnw("Failed to get migration version", "error", err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(dto.Response{
+		Status: "success",
+		Data:   data,
+	})
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"simple-service/internal/api/handlers"
+	"simple-service/internal/dto"
+	"simple-service/internal/repo/mocks"
+	"simple-service/internal/service"
+)
+
+func TestHealth(t *testing.T) {
+	mockRepo := new(mocks.Repository)
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core).Sugar()
+	health := NewHealth(mockRepo, logger)
+
+	// Авторизация, которая отклоняет все запросы: проверки состояния должны работать без неё
+	deny := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusUnauthorized) }
+	app := NewRouters(&Routers{
+		Handlers: handlers.New(service.NewTaskService(mockRepo), logger),
+		Health:   health,
+		Log:      logger,
+	}, deny)
+
+	get := func(t *testing.T, path string) (*http.Response, dto.Response) {
+		req, err := http.NewRequest("GET", path, nil)
+		assert.NoError(t, err)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var response dto.Response
+		json.NewDecoder(resp.Body).Decode(&response)
+		return resp, response
+	}
+
+	t.Run("liveness без авторизации", func(t *testing.T) {
+		resp, _ := get(t, "/healthz")
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("готов к работе", func(t *testing.T) {
+		mockRepo.On("Ping", mock.Anything).Return(nil).Once()
+		mockRepo.On("MigrationVersion", mock.Anything).Return(5, nil).Once()
+
+		resp, response := get(t, "/readyz")
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, map[string]any{"database": "ok", "migration_version": float64(5)}, response.Data)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("версия миграций недоступна", func(t *testing.T) {
+		logs.TakeAll()
+		mockRepo.On("Ping", mock.Anything).Return(nil).Times(3)
+		mockRepo.On("MigrationVersion", mock.Anything).Return(0, errors.New("no table")).Twice()
+
+		resp, response := get(t, "/readyz")
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, map[string]any{"database": "ok"}, response.Data)
+
+		// Повторная ошибка не пишется в лог, пока версия снова не станет доступна
+		get(t, "/readyz")
+		assert.Equal(t, 1, logs.FilterMessage("Failed to get migration version").Len())
+
+		mockRepo.On("MigrationVersion", mock.Anything).Return(5, nil).Once()
+		get(t, "/readyz")
+		assert.Equal(t, 1, logs.FilterMessage("Migration version is available again").Len())
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("БД недоступна", func(t *testing.T) {
+		mockRepo.On("Ping", mock.Anything).Return(errors.New("connection refused")).Once()
+
+		resp, response := get(t, "/readyz")
+		assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, dto.ServiceUnavailable, response.Error.Code)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("остановка сервиса", func(t *testing.T) {
+		health.Drain()
+
+		resp, response := get(t, "/readyz")
+		assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, shuttingDown, response.Error.Code)
+
+		// Liveness не зависит от остановки: процесс ещё жив
+		resp, _ = get(t, "/healthz")
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("API требует авторизации", func(t *testing.T) {
+		resp, _ := get(t, "/v1/tasks")
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+}
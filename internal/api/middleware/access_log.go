@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/config"
+	"simple-service/internal/tracing"
+)
+
+// AccessLog - одна запись лога на каждый запрос. Запросы с ошибкой сервера записываются всегда,
+// остальные - с вероятностью cfg.SampleRate. Пути из cfg.ExcludePaths не записываются.
+// Если до AccessLog подключён Tracing, запись содержит trace_id и span_id запроса
+func AccessLog(log *zap.SugaredLogger, cfg config.AccessLog) fiber.Handler {
+	excluded := make(map[string]struct{}, len(cfg.ExcludePaths))
+	for _, path := range cfg.ExcludePaths {
+		excluded[path] = struct{}{}
+	}
+
+	return func(c *fiber.Ctx) error {
+		if _, ok := excluded[c.Path()]; ok {
+			return c.Next()
+		}
+
+		start := time.Now()
+		err := c.Next()
+		latency := time.Since(start)
+
+		status := responseStatus(c, err)
+		serverError := status >= fiber.StatusInternalServerError
+		if !serverError && (cfg.SampleRate <= 0 || cfg.SampleRate < 1 && rand.Float64() >= cfg.SampleRate) {
+			return err
+		}
+
+		fields := []any{
+			"method", c.Method(),
+			"route", c.Route().Path,
+			"status", status,
+			"latency_ms", float64(latency.Microseconds()) / 1000,
+			"bytes_in", len(c.Request().Body()),
+			"bytes_out", len(c.Response().Body()),
+			"ip", c.IP(),
+			"user_agent", c.Get(fiber.HeaderUserAgent),
+			"request_id", RequestIDFrom(c),
+		}
+		if principal, ok := auth.PrincipalFrom(c); ok {
+			fields = append(fields, "principal", principal.Subject)
+		}
+		fields = append(fields, tracing.LogFields(c.UserContext())...)
+
+		if serverError {
+			log.Errorw("HTTP request", fields...)
+		} else {
+			log.Infow("HTTP request", fields...)
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"simple-service/internal/config"
+)
+
+func TestAccessLog(t *testing.T) {
+	newApp := func(cfg config.AccessLog) (*fiber.App, *observer.ObservedLogs) {
+		core, logs := observer.New(zap.InfoLevel)
+		log := zap.New(core).Sugar()
+
+		app := fiber.New()
+		app.Use(RequestID(log))
+		app.Use(AccessLog(log, cfg))
+		app.Get("/healthz", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		app.Post("/tasks/:id", func(c *fiber.Ctx) error {
+			if c.Params("id") == "broken" {
+				return fiber.ErrInternalServerError
+			}
+			return c.SendString("created")
+		})
+		return app, logs
+	}
+
+	t.Run("Запись содержит данные запроса", func(t *testing.T) {
+		app, logs := newApp(config.AccessLog{SampleRate: 1})
+
+		req, _ := http.NewRequest(http.MethodPost, "/tasks/1", strings.NewReader(`{"title":"x"}`))
+		req.Header.Set(fiber.HeaderUserAgent, "todo-cli")
+		req.Header.Set(fiber.HeaderXRequestID, "req-1")
+		_, err := app.Test(req)
+		require.NoError(t, err)
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, http.MethodPost, fields["method"])
+		assert.Equal(t, "/tasks/:id", fields["route"])
+		assert.EqualValues(t, http.StatusOK, fields["status"])
+		assert.EqualValues(t, len(`{"title":"x"}`), fields["bytes_in"])
+		assert.EqualValues(t, len("created"), fields["bytes_out"])
+		assert.Equal(t, "todo-cli", fields["user_agent"])
+		assert.Equal(t, "req-1", fields["request_id"])
+		assert.Contains(t, fields, "latency_ms")
+		assert.Contains(t, fields, "ip")
+	})
+
+	t.Run("Исключённые пути не записываются", func(t *testing.T) {
+		app, logs := newApp(config.AccessLog{SampleRate: 1, ExcludePaths: []string{"/healthz"}})
+
+		req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+		_, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Zero(t, logs.Len())
+	})
+
+	t.Run("Без сэмплирования записываются только ошибки сервера", func(t *testing.T) {
+		app, logs := newApp(config.AccessLog{SampleRate: 0})
+
+		req, _ := http.NewRequest(http.MethodPost, "/tasks/1", nil)
+		_, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Zero(t, logs.Len())
+
+		req, _ = http.NewRequest(http.MethodPost, "/tasks/broken", nil)
+		_, err = app.Test(req)
+		require.NoError(t, err)
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		assert.Equal(t, zapcore.ErrorLevel, entries[0].Level)
+		assert.EqualValues(t, http.StatusInternalServerError, entries[0].ContextMap()["status"])
+	})
+}
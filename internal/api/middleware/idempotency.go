@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/dto"
+	logging "simple-service/internal/logger"
+	"simple-service/internal/repo"
+)
+
+// Повтор запроса с тем же заголовком Idempotency-Key возвращает сохранённый ответ
+// вместо повторного выполнения
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyStore - хранилище ключей идемпотентности
+type IdempotencyStore interface {
+	ReserveIdempotencyKey(ctx context.Context, owner, key, requestHash string, ttl, lockTimeout time.Duration) (*repo.IdempotencyRecord, error)
+	SaveIdempotentResponse(ctx context.Context, owner, key string, statusCode int, response []byte) error
+	DeleteIdempotencyKey(ctx context.Context, owner, key string) error
+}
+
+// Idempotency - обработка заголовка Idempotency-Key. Ключ резервируется за пользователем на ttl,
+// ответы без ошибки сервера сохраняются и возвращаются при повторе. Повтор ключа с другим телом
+// запроса отклоняется. Если ответ так и не сохранён (сервис упал или БД вернула ошибку),
+// резерв освобождается через lockTimeout. Запросы без заголовка выполняются как обычно
+func Idempotency(store IdempotencyStore, ttl, lockTimeout time.Duration, log *zap.SugaredLogger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return dto.BadResponseError(c, dto.FieldIncorrect, "Idempotency-Key is too long")
+		}
+
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			return unauthorized(c, dto.Unauthorized, "Unauthorized")
+		}
+
+		// Контекст запоминаем до обработчика: сервис подменяет UserContext на контекст своего span
+		ctx := c.UserContext()
+		reqLog := logging.FromContext(c, log)
+		hash := requestHash(c)
+
+		record, err := store.ReserveIdempotencyKey(ctx, principal.Subject, key, hash, ttl, lockTimeout)
+		if err != nil {
+			reqLog.Errorw("Failed to reserve idempotency key", "error", err)
+			return dto.InternalServerError(c)
+		}
+		if record != nil {
+			switch {
+			case record.RequestHash != hash:
+				return dto.ConflictError(c, dto.IdempotencyReused, "Idempotency-Key was already used with a different request")
+			case record.StatusCode == 0:
+				return dto.ConflictError(c, dto.IdempotencyPending, "Request with this Idempotency-Key is still in progress")
+			}
+			c.Set(HeaderIdempotentReplayed, "true")
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(record.StatusCode).Send(record.Response)
+		}
+
+		err = c.Next()
+
+		// После ошибки сервера резерв снимается, чтобы клиент мог повторить запрос
+		if status := responseStatus(c, err); err != nil || status >= fiber.StatusInternalServerError {
+			if dErr := store.DeleteIdempotencyKey(ctx, principal.Subject, key); dErr != nil {
+				reqLog.Errorw("Failed to release idempotency key", "error", dErr)
+			}
+			return err
+		}
+
+		if sErr := store.SaveIdempotentResponse(ctx, principal.Subject, key, c.Response().StatusCode(), c.Response().Body()); sErr != nil {
+			reqLog.Errorw("Failed to save idempotent response", "error", sErr)
+		}
+		return nil
+	}
+}
+
+// requestHash - отпечаток запроса для сравнения повторов с одним ключом
+func requestHash(c *fiber.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(c.Method()))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(c.Path()))
+	h.Write([]byte{'\n'})
+	h.Write(c.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/dto"
+	"simple-service/internal/repo"
+	"simple-service/internal/repo/mocks"
+)
+
+func TestIdempotency(t *testing.T) {
+	const (
+		key  = "key-1"
+		body = `{"title":"Task"}`
+		ttl  = time.Hour
+		lock = time.Minute
+	)
+
+	newApp := func(store IdempotencyStore, handler fiber.Handler) *fiber.App {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			auth.SetPrincipal(c, auth.Principal{Subject: "alice"})
+			return c.Next()
+		})
+		app.Post("/create_task", Idempotency(store, ttl, lock, zap.NewNop().Sugar()), handler)
+		return app
+	}
+
+	sum := sha256.Sum256([]byte("POST\n/create_task\n" + body))
+	bodyHash := hex.EncodeToString(sum[:])
+
+	send := func(app *fiber.App, key string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, "/create_task", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	created := func(calls *int) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			*calls++
+			return c.Status(fiber.StatusOK).JSON(dto.Response{Status: "success", Data: map[string]int{"task_id": 7}})
+		}
+	}
+
+	errorCode := func(t *testing.T, resp *http.Response) string {
+		var response dto.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		require.NotNil(t, response.Error)
+		return response.Error.Code
+	}
+
+	t.Run("Без заголовка запрос выполняется как обычно", func(t *testing.T) {
+		store := new(mocks.Repository)
+		calls := 0
+
+		resp := send(newApp(store, created(&calls)), "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 1, calls)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("Первый запрос выполняется и ответ сохраняется", func(t *testing.T) {
+		store := new(mocks.Repository)
+		calls := 0
+		store.On("ReserveIdempotencyKey", mock.Anything, "alice", key, bodyHash, ttl, lock).Return(nil, nil).Once()
+		store.On("SaveIdempotentResponse", mock.Anything, "alice", key, http.StatusOK,
+			[]byte(`{"status":"success","data":{"task_id":7}}`)).Return(nil).Once()
+
+		resp := send(newApp(store, created(&calls)), key)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(HeaderIdempotentReplayed))
+		assert.Equal(t, 1, calls)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("Повтор возвращает сохранённый ответ", func(t *testing.T) {
+		store := new(mocks.Repository)
+		calls := 0
+		stored := `{"status":"success","data":{"task_id":7}}`
+		store.On("ReserveIdempotencyKey", mock.Anything, "alice", key, bodyHash, ttl, lock).Return(&repo.IdempotencyRecord{
+			RequestHash: bodyHash,
+			StatusCode:  http.StatusOK,
+			Response:    []byte(stored),
+		}, nil).Once()
+
+		resp := send(newApp(store, created(&calls)), key)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "true", resp.Header.Get(HeaderIdempotentReplayed))
+		got, _ := io.ReadAll(resp.Body)
+		assert.JSONEq(t, stored, string(got))
+		assert.Zero(t, calls)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("Ключ с другим телом запроса", func(t *testing.T) {
+		store := new(mocks.Repository)
+		calls := 0
+		store.On("ReserveIdempotencyKey", mock.Anything, "alice", key, bodyHash, ttl, lock).Return(&repo.IdempotencyRecord{
+			RequestHash: "other",
+			StatusCode:  http.StatusOK,
+		}, nil).Once()
+
+		resp := send(newApp(store, created(&calls)), key)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, dto.IdempotencyReused, errorCode(t, resp))
+		assert.Zero(t, calls)
+	})
+
+	t.Run("Первый запрос ещё выполняется", func(t *testing.T) {
+		store := new(mocks.Repository)
+		calls := 0
+		store.On("ReserveIdempotencyKey", mock.Anything, "alice", key, bodyHash, ttl, lock).Return(&repo.IdempotencyRecord{
+			RequestHash: bodyHash,
+		}, nil).Once()
+
+		resp := send(newApp(store, created(&calls)), key)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, dto.IdempotencyPending, errorCode(t, resp))
+		assert.Zero(t, calls)
+	})
+
+	t.Run("После ошибки сервера резерв снимается", func(t *testing.T) {
+		store := new(mocks.Repository)
+		store.On("ReserveIdempotencyKey", mock.Anything, "alice", key, bodyHash, ttl, lock).Return(nil, nil).Once()
+		store.On("DeleteIdempotencyKey", mock.Anything, "alice", key).Return(nil).Once()
+
+		resp := send(newApp(store, dto.InternalServerError), key)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("Слишком длинный ключ", func(t *testing.T) {
+		store := new(mocks.Repository)
+		calls := 0
+
+		resp := send(newApp(store, created(&calls)), strings.Repeat("k", maxIdempotencyKeyLength+1))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Zero(t, calls)
+	})
+}
@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/config"
+	"simple-service/internal/dto"
+)
+
+const testSecret = "secret"
+
+// jwtClaims - claims токена, который выпускает шлюз
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles"`
+}
+
+// newJWTApp - приложение, которое возвращает subject и роли авторизованного пользователя
+func newJWTApp(t *testing.T, cfg config.JWT) *fiber.App {
+	authenticator, err := auth.NewAuthenticator(config.Rest{AuthMode: auth.ModeJWT, JWT: cfg, DefaultRole: auth.RoleViewer})
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(Authenticate(authenticator))
+	app.Get("/", func(c *fiber.Ctx) error {
+		principal, _ := auth.PrincipalFrom(c)
+		return c.SendString(principal.Subject + ":" + strings.Join(principal.Roles, ","))
+	})
+	return app
+}
+
+func signHS256(t *testing.T, claims jwtClaims, secret string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func validClaims() jwtClaims {
+	return jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "alice",
+			Issuer:    "gateway",
+			Audience:  jwt.ClaimStrings{"simple-service"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles: []string{"editor"},
+	}
+}
+
+func TestJWT(t *testing.T) {
+	app := newJWTApp(t, config.JWT{
+		Algorithm: "HS256",
+		Secret:    testSecret,
+		Issuer:    "gateway",
+		Audience:  "simple-service",
+		ClockSkew: time.Minute,
+	})
+
+	expired := validClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+
+	withinSkew := validClaims()
+	withinSkew.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-30 * time.Second))
+
+	wrongIssuer := validClaims()
+	wrongIssuer.Issuer = "other"
+
+	wrongAudience := validClaims()
+	wrongAudience.Audience = jwt.ClaimStrings{"other"}
+
+	noSubject := validClaims()
+	noSubject.Subject = ""
+
+	noRoles := validClaims()
+	noRoles.Roles = nil
+
+	noExpiration := validClaims()
+	noExpiration.ExpiresAt = nil
+
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantCode   string
+		wantBody   string
+	}{
+		{name: "валидный токен", token: signHS256(t, validClaims(), testSecret), wantStatus: fiber.StatusOK, wantBody: "alice:editor"},
+		{name: "роль по умолчанию", token: signHS256(t, noRoles, testSecret), wantStatus: fiber.StatusOK, wantBody: "alice:viewer"},
+		{name: "истёк в пределах допуска", token: signHS256(t, withinSkew, testSecret), wantStatus: fiber.StatusOK, wantBody: "alice:editor"},
+		{name: "истёкший токен", token: signHS256(t, expired, testSecret), wantStatus: fiber.StatusUnauthorized, wantCode: dto.TokenExpired},
+		{name: "чужая подпись", token: signHS256(t, validClaims(), "other"), wantStatus: fiber.StatusUnauthorized, wantCode: dto.TokenInvalid},
+		{name: "другой издатель", token: signHS256(t, wrongIssuer, testSecret), wantStatus: fiber.StatusUnauthorized, wantCode: dto.TokenInvalid},
+		{name: "другая аудитория", token: signHS256(t, wrongAudience, testSecret), wantStatus: fiber.StatusUnauthorized, wantCode: dto.TokenInvalid},
+		{name: "без subject", token: signHS256(t, noSubject, testSecret), wantStatus: fiber.StatusUnauthorized, wantCode: dto.TokenInvalid},
+		{name: "без срока действия", token: signHS256(t, noExpiration, testSecret), wantStatus: fiber.StatusUnauthorized, wantCode: dto.TokenInvalid},
+		{name: "алгоритм none", token: none, wantStatus: fiber.StatusUnauthorized, wantCode: dto.TokenInvalid},
+		{name: "без токена", wantStatus: fiber.StatusUnauthorized, wantCode: dto.Unauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/", nil)
+			require.NoError(t, err)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantBody != "" {
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, tt.wantBody, string(body))
+			}
+			if tt.wantCode != "" {
+				var response dto.Response
+				json.NewDecoder(resp.Body).Decode(&response)
+				assert.Equal(t, tt.wantCode, response.Error.Code)
+			}
+		})
+	}
+}
+
+func TestJWTRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "public.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	app := newJWTApp(t, config.JWT{Algorithm: "RS256", PublicKeyPath: path})
+
+	t.Run("подпись закрытым ключом", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims()).SignedString(key)
+		require.NoError(t, err)
+
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("HS256 вместо RS256", func(t *testing.T) {
+		// Классическая атака: подпись HS256 публичным ключом
+		token := signHS256(t, validClaims(), string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"simple-service/internal/metrics"
+)
+
+// Metrics - учёт количества и длительности запросов по шаблону маршрута и статусу ответа
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		labels := []string{c.Method(), c.Route().Path, strconv.Itoa(responseStatus(c, err))}
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.HTTPDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// responseStatus - итоговый статус ответа. Ошибку из обработчика fiber превратит в ответ
+// уже после миддлваеров, поэтому статус берётся из неё
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	var fErr *fiber.Error
+	if errors.As(err, &fErr) {
+		return fErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"simple-service/internal/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	app := fiber.New()
+	app.Use(Metrics())
+	app.Get("/metrics-test/:id", func(c *fiber.Ctx) error {
+		if c.Params("id") == "missing" {
+			return fiber.ErrNotFound
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Post("/metrics-test", func(c *fiber.Ctx) error {
+		return fiber.ErrInternalServerError
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		route  string
+		status string
+	}{
+		{
+			name:   "Успешный запрос учитывается по шаблону маршрута",
+			method: http.MethodGet,
+			target: "/metrics-test/1",
+			route:  "/metrics-test/:id",
+			status: "200",
+		},
+		{
+			name:   "Статус берётся из ошибки fiber",
+			method: http.MethodGet,
+			target: "/metrics-test/missing",
+			route:  "/metrics-test/:id",
+			status: "404",
+		},
+		{
+			name:   "Ошибка обработчика учитывается как 500",
+			method: http.MethodPost,
+			target: "/metrics-test",
+			route:  "/metrics-test",
+			status: "500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metrics.HTTPRequests.WithLabelValues(tt.method, tt.route, tt.status)
+			before := testutil.ToFloat64(counter)
+
+			req, _ := http.NewRequest(tt.method, tt.target, nil)
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.Status[:3])
+
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
@@ -1,14 +1,64 @@
 package middleware
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/dto"
+	logging "simple-service/internal/logger"
 )
 
 // Обычный миддлваер
 
-func Authorization(token string) fiber.Handler {
+const bearerPrefix = "Bearer "
+
+// Authenticate - проверка заголовка Authorization: Bearer <token>,
+// пользователь, которому принадлежит токен, сохраняется в контексте запроса
+func Authenticate(authenticator auth.Authenticator) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// проверка токена вторизации
+		token, ok := bearerToken(c)
+		if !ok {
+			return unauthorized(c, dto.Unauthorized, "Missing bearer token")
+		}
+
+		principal, err := authenticator.Authenticate(token)
+		switch {
+		case errors.Is(err, auth.ErrTokenExpired):
+			return unauthorized(c, dto.TokenExpired, "Token is expired")
+		case errors.Is(err, auth.ErrNoSubject):
+			return unauthorized(c, dto.TokenInvalid, "Token has no subject")
+		case errors.Is(err, auth.ErrTokenInvalid):
+			return unauthorized(c, dto.TokenInvalid, "Token is invalid")
+		case err != nil:
+			return unauthorized(c, dto.Unauthorized, "Invalid bearer token")
+		}
+
+		setPrincipal(c, principal)
 		return c.Next()
 	}
 }
+
+// setPrincipal - сохранение пользователя в контексте запроса, логгер запроса дополняется его именем
+func setPrincipal(c *fiber.Ctx, p auth.Principal) {
+	auth.SetPrincipal(c, p)
+	if log := logging.FromContext(c, nil); log != nil {
+		logging.SetRequestLogger(c, log.With("principal", p.Subject))
+	}
+}
+
+// bearerToken - токен из заголовка Authorization: Bearer <token>
+func bearerToken(c *fiber.Ctx) (string, bool) {
+	header := c.Get(fiber.HeaderAuthorization)
+	if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	return header[len(bearerPrefix):], true
+}
+
+func unauthorized(c *fiber.Ctx, code, desc string) error {
+	c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+	return dto.UnauthorizedError(c, code, desc)
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/config"
+	"simple-service/internal/dto"
+)
+
+func TestAuthenticate(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator(config.Rest{
+		AuthMode: auth.ModeToken,
+		Tokens: config.Credentials{
+			{Subject: "alice", Token: "old-token"},
+			{Subject: "alice", Token: "new-token"},
+			{Subject: "bob", Token: "bob-token"},
+		},
+		Roles:       map[string]string{"bob": auth.RoleAdmin},
+		DefaultRole: auth.RoleEditor,
+	})
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(Authenticate(authenticator))
+	app.Get("/", func(c *fiber.Ctx) error {
+		principal, _ := auth.PrincipalFrom(c)
+		return c.SendString(principal.Subject + ":" + strings.Join(principal.Roles, ","))
+	})
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantUser   string
+	}{
+		{name: "актуальный токен", header: "Bearer new-token", wantStatus: fiber.StatusOK, wantUser: "alice:editor"},
+		{name: "предыдущий токен при ротации", header: "Bearer old-token", wantStatus: fiber.StatusOK, wantUser: "alice:editor"},
+		{name: "другой пользователь", header: "Bearer bob-token", wantStatus: fiber.StatusOK, wantUser: "bob:admin"},
+		{name: "схема в нижнем регистре", header: "bearer new-token", wantStatus: fiber.StatusOK, wantUser: "alice:editor"},
+		{name: "без заголовка", header: "", wantStatus: fiber.StatusUnauthorized},
+		{name: "неверный токен", header: "Bearer wrong", wantStatus: fiber.StatusUnauthorized},
+		{name: "пустой токен", header: "Bearer ", wantStatus: fiber.StatusUnauthorized},
+		{name: "другая схема", header: "Basic bmV3LXRva2Vu", wantStatus: fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/", nil)
+			assert.NoError(t, err)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantStatus == fiber.StatusOK {
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, tt.wantUser, string(body))
+			}
+			if tt.wantStatus == fiber.StatusUnauthorized {
+				var response dto.Response
+				json.NewDecoder(resp.Body).Decode(&response)
+				assert.Equal(t, "error", response.Status)
+				assert.Equal(t, dto.Unauthorized, response.Error.Code)
+				assert.Equal(t, "Bearer", resp.Header.Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/dto"
+	logging "simple-service/internal/logger"
+)
+
+// Проверка прав пользователя на маршрут, выполняется после авторизации
+
+// Require - пропускает запрос, только если у пользователя есть право perm
+func Require(log *zap.SugaredLogger, perm auth.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			return unauthorized(c, dto.Unauthorized, "Unauthorized")
+		}
+
+		if !principal.Can(perm) {
+			logging.FromContext(c, log).Warnw("Access denied",
+				"subject", principal.Subject,
+				"roles", principal.Roles,
+				"permission", perm,
+				"method", c.Method(),
+				"route", c.Route().Path,
+			)
+			return dto.ForbiddenError(c, dto.Forbidden, "Not enough permissions")
+		}
+
+		return c.Next()
+	}
+}
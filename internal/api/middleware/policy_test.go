@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/dto"
+)
+
+func TestRequire(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	newApp := func(principal *auth.Principal) *fiber.App {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			if principal != nil {
+				auth.SetPrincipal(c, *principal)
+			}
+			return c.Next()
+		})
+		app.Get("/tasks", Require(logger, auth.PermRead), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		app.Post("/tasks", Require(logger, auth.PermWrite), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		return app
+	}
+
+	viewer := &auth.Principal{Subject: "alice", Roles: []string{auth.RoleViewer}}
+	editor := &auth.Principal{Subject: "bob", Roles: []string{auth.RoleEditor}}
+
+	tests := []struct {
+		name       string
+		principal  *auth.Principal
+		method     string
+		wantStatus int
+	}{
+		{name: "viewer читает", principal: viewer, method: "GET", wantStatus: fiber.StatusOK},
+		{name: "viewer не может писать", principal: viewer, method: "POST", wantStatus: fiber.StatusForbidden},
+		{name: "editor пишет", principal: editor, method: "POST", wantStatus: fiber.StatusOK},
+		{name: "без пользователя", principal: nil, method: "GET", wantStatus: fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "/tasks", nil)
+			assert.NoError(t, err)
+
+			resp, err := newApp(tt.principal).Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantStatus == fiber.StatusForbidden {
+				var response dto.Response
+				json.NewDecoder(resp.Body).Decode(&response)
+				assert.Equal(t, dto.Forbidden, response.Error.Code)
+			}
+		})
+	}
+}
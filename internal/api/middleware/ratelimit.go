@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/dto"
+	"simple-service/internal/ratelimit"
+)
+
+// RateLimit - ограничение частоты запросов для каждого пользователя.
+// Если пользователь не авторизован, ограничение считается по IP клиента
+func RateLimit(limiter *ratelimit.Limiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := "ip:" + c.IP()
+		if principal, ok := auth.PrincipalFrom(c); ok {
+			key = "user:" + principal.Subject
+		}
+
+		ok, wait := limiter.Allow(key)
+		if !ok {
+			// Retry-After задаётся в целых секундах, округляем вверх
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			return dto.TooManyRequestsError(c, dto.RateLimited, "Too many requests")
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/dto"
+	"simple-service/internal/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if subject := c.Get("X-Test-User"); subject != "" {
+			auth.SetPrincipal(c, auth.Principal{Subject: subject})
+		}
+		return c.Next()
+	})
+	app.Get("/tasks", RateLimit(ratelimit.NewLimiter(0.5, 1)), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	send := func(user string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+		if user != "" {
+			req.Header.Set("X-Test-User", user)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Превышение лимита пользователем", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send("alice").StatusCode)
+
+		resp := send("alice")
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get(fiber.HeaderRetryAfter))
+
+		var body dto.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.NotNil(t, body.Error)
+		assert.Equal(t, dto.RateLimited, body.Error.Code)
+	})
+
+	t.Run("Лимит другого пользователя не затронут", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send("bob").StatusCode)
+	})
+
+	t.Run("Без пользователя лимит считается по IP", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send("").StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, send("").StatusCode)
+	})
+}
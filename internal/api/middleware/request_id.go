@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	logging "simple-service/internal/logger"
+	"simple-service/internal/requestid"
+	"simple-service/internal/tracing"
+)
+
+// Идентификатор запроса и логгер запроса
+
+const requestIDLocalsKey = "request_id"
+
+// RequestID - берёт X-Request-ID из запроса или генерирует новый, возвращает его в ответе
+// и сохраняет в контексте логгер запроса с request_id, методом и путём.
+// Подключается после Tracing, тогда логгер запроса получает и trace_id со span_id
+func RequestID(log *zap.SugaredLogger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := requestid.FromClient(c.Get(fiber.HeaderXRequestID))
+
+		c.Locals(requestIDLocalsKey, requestID)
+		c.Set(fiber.HeaderXRequestID, requestID)
+		logging.SetRequestLogger(c, log.With(
+			"request_id", requestID,
+			"method", c.Method(),
+			"path", c.Path(),
+		).With(tracing.LogFields(c.UserContext())...))
+
+		return c.Next()
+	}
+}
+
+// RequestIDFrom - идентификатор текущего запроса
+func RequestIDFrom(c *fiber.Ctx) string {
+	requestID, _ := c.Locals(requestIDLocalsKey).(string)
+	return requestID
+}
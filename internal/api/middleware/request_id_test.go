@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/config"
+	logging "simple-service/internal/logger"
+	"simple-service/internal/requestid"
+)
+
+func TestRequestID(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+
+	app := fiber.New()
+	app.Use(RequestID(zap.New(core).Sugar()))
+	app.Get("/tasks", Authenticate(auth.NewTokenAuthenticator(config.Credentials{{Subject: "alice", Token: "secret"}}, nil, auth.RoleEditor)),
+		func(c *fiber.Ctx) error {
+			logging.FromContext(c, nil).Info("handled")
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+	tests := []struct {
+		name      string
+		requestID string
+		wantSame  bool
+	}{
+		{
+			name:      "Идентификатор клиента возвращается в ответе",
+			requestID: "req-42",
+			wantSame:  true,
+		},
+		{
+			name: "Идентификатор генерируется, если его нет",
+		},
+		{
+			name:      "Идентификатор с недопустимыми символами заменяется",
+			requestID: "bad id\n",
+		},
+		{
+			name:      "Слишком длинный идентификатор заменяется",
+			requestID: strings.Repeat("a", requestid.MaxLength+1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.TakeAll()
+
+			req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			if tt.requestID != "" {
+				req.Header.Set(fiber.HeaderXRequestID, tt.requestID)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			got := resp.Header.Get(fiber.HeaderXRequestID)
+			if tt.wantSame {
+				assert.Equal(t, tt.requestID, got)
+			} else {
+				assert.Len(t, got, 32)
+			}
+
+			// Логгер запроса дополнен данными запроса и пользователем
+			entries := logs.TakeAll()
+			require.Len(t, entries, 1)
+			fields := entries[0].ContextMap()
+			assert.Equal(t, got, fields["request_id"])
+			assert.Equal(t, http.MethodGet, fields["method"])
+			assert.Equal(t, "/tasks", fields["path"])
+			assert.Equal(t, "alice", fields["principal"])
+		})
+	}
+}
+
+func TestRequestIDTraceFields(t *testing.T) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	core, logs := observer.New(zap.InfoLevel)
+	log := zap.New(core).Sugar()
+
+	app := fiber.New()
+	app.Use(Tracing(), RequestID(log), AccessLog(log, config.AccessLog{SampleRate: 1}))
+	app.Get("/tasks", func(c *fiber.Ctx) error {
+		logging.FromContext(c, nil).Info("handled")
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+
+	// И логгер запроса, и журнал запросов содержат trace_id входящего запроса
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry.ContextMap()["trace_id"], entry.Message)
+		assert.NotEmpty(t, entry.ContextMap()["span_id"], entry.Message)
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"simple-service/internal/tracing"
+)
+
+// Tracing - серверный span на каждый запрос. Родительский контекст берётся из заголовка traceparent,
+// span сохраняется в UserContext и становится родителем для span сервиса и репозитория
+func Tracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{&c.Request().Header})
+		ctx, span := tracing.Tracer().Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+			),
+		)
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		// Шаблон маршрута известен только после выбора обработчика
+		status := responseStatus(c, err)
+		span.SetName(c.Method() + " " + c.Route().Path)
+		span.SetAttributes(
+			semconv.HTTPRoute(c.Route().Path),
+			semconv.HTTPResponseStatusCode(status),
+		)
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+
+		return err
+	}
+}
+
+// headerCarrier - заголовки fasthttp для пропагатора OpenTelemetry
+type headerCarrier struct {
+	header *fasthttp.RequestHeader
+}
+
+// Get - значение заголовка
+func (h headerCarrier) Get(key string) string {
+	return string(h.header.Peek(key))
+}
+
+// Set - установка заголовка
+func (h headerCarrier) Set(key, value string) {
+	h.header.Set(key, value)
+}
+
+// Keys - имена всех заголовков запроса
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, h.header.Len())
+	h.header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
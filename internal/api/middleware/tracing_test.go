@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	app := fiber.New()
+	app.Use(Tracing())
+	app.Get("/tasks/:id", func(c *fiber.Ctx) error {
+		// Обработчик получает контекст с серверным span
+		assert.True(t, trace.SpanContextFromContext(c.UserContext()).IsValid())
+		if c.Params("id") == "broken" {
+			return fiber.ErrInternalServerError
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	t.Run("Родительский span из traceparent", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/tasks/1", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		_, err := app.Test(req)
+		require.NoError(t, err)
+
+		spans := recorder.Ended()
+		require.NotEmpty(t, spans)
+		span := spans[len(spans)-1]
+		assert.Equal(t, "GET /tasks/:id", span.Name())
+		assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+		assert.Contains(t, span.Attributes(), semconv.HTTPResponseStatusCode(http.StatusOK))
+		assert.Equal(t, codes.Unset, span.Status().Code)
+	})
+
+	t.Run("Ошибка сервера отмечается в span", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/tasks/broken", nil)
+
+		_, err := app.Test(req)
+		require.NoError(t, err)
+
+		spans := recorder.Ended()
+		span := spans[len(spans)-1]
+		assert.False(t, span.Parent().IsValid())
+		assert.Contains(t, span.Attributes(), semconv.HTTPResponseStatusCode(http.StatusInternalServerError))
+		assert.Equal(t, codes.Error, span.Status().Code)
+	})
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"simple-service/internal/config"
+)
+
+// Проверка bearer-токенов, общая для HTTP и gRPC
+
+const (
+	ModeToken = "token"
+	ModeJWT   = "jwt"
+)
+
+var (
+	// ErrUnknownToken - статический токен не найден в списке
+	ErrUnknownToken = errors.New("unknown bearer token")
+	// ErrTokenExpired - срок действия JWT истёк
+	ErrTokenExpired = errors.New("token is expired")
+	// ErrTokenInvalid - неверная подпись, издатель, аудитория или формат JWT
+	ErrTokenInvalid = errors.New("token is invalid")
+	// ErrNoSubject - в JWT нет subject
+	ErrNoSubject = errors.New("token has no subject")
+)
+
+// Authenticator - проверка bearer-токена и получение пользователя, которому он принадлежит
+type Authenticator interface {
+	Authenticate(token string) (Principal, error)
+}
+
+// NewAuthenticator - проверка токенов для режима, выбранного в конфигурации
+func NewAuthenticator(cfg config.Rest) (Authenticator, error) {
+	switch cfg.AuthMode {
+	case ModeToken:
+		if len(cfg.Tokens) == 0 {
+			return nil, fmt.Errorf("TOKEN is required for auth mode %q", ModeToken)
+		}
+		return NewTokenAuthenticator(cfg.Tokens, cfg.Roles, cfg.DefaultRole), nil
+	case ModeJWT:
+		return NewJWTAuthenticator(cfg.JWT, cfg.DefaultRole)
+	default:
+		return nil, fmt.Errorf("unknown auth mode %q", cfg.AuthMode)
+	}
+}
+
+type tokenAuthenticator struct {
+	creds       config.Credentials
+	hashes      [][sha256.Size]byte
+	roles       map[string]string
+	defaultRole string
+}
+
+// NewTokenAuthenticator - проверка статических токенов, роль пользователя берётся из roles
+func NewTokenAuthenticator(creds config.Credentials, roles map[string]string, defaultRole string) Authenticator {
+	// Сравниваем хеши, чтобы время сравнения не зависело от длины токена
+	hashes := make([][sha256.Size]byte, len(creds))
+	for i, cred := range creds {
+		hashes[i] = sha256.Sum256([]byte(cred.Token))
+	}
+	return &tokenAuthenticator{creds: creds, hashes: hashes, roles: roles, defaultRole: defaultRole}
+}
+
+func (a *tokenAuthenticator) Authenticate(token string) (Principal, error) {
+	// Проверяем все токены, не прерываясь на первом совпадении
+	got := sha256.Sum256([]byte(token))
+	subject := ""
+	for i := range a.hashes {
+		if subtle.ConstantTimeCompare(got[:], a.hashes[i][:]) == 1 {
+			subject = a.creds[i].Subject
+		}
+	}
+	if subject == "" {
+		return Principal{}, ErrUnknownToken
+	}
+
+	var roles []string
+	if role, ok := a.roles[subject]; ok {
+		roles = []string{role}
+	}
+	return Principal{Subject: subject, Roles: withDefaultRole(roles, a.defaultRole)}, nil
+}
+
+// withDefaultRole - роли пользователя или роль по умолчанию, если роли не заданы
+func withDefaultRole(roles []string, defaultRole string) []string {
+	if len(roles) == 0 && defaultRole != "" {
+		return []string{defaultRole}
+	}
+	return roles
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"simple-service/internal/config"
+)
+
+func TestNewAuthenticator(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Rest
+		wantErr bool
+	}{
+		{name: "токены", cfg: config.Rest{AuthMode: ModeToken, Tokens: config.Credentials{{Subject: "a", Token: "b"}}}},
+		{name: "токены не заданы", cfg: config.Rest{AuthMode: ModeToken}, wantErr: true},
+		{name: "JWT HS256", cfg: config.Rest{AuthMode: ModeJWT, JWT: config.JWT{Algorithm: "HS256", Secret: "s"}}},
+		{name: "JWT без секрета", cfg: config.Rest{AuthMode: ModeJWT, JWT: config.JWT{Algorithm: "HS256"}}, wantErr: true},
+		{name: "JWT без ключа", cfg: config.Rest{AuthMode: ModeJWT, JWT: config.JWT{Algorithm: "RS256"}}, wantErr: true},
+		{name: "неизвестный алгоритм", cfg: config.Rest{AuthMode: ModeJWT, JWT: config.JWT{Algorithm: "ES256"}}, wantErr: true},
+		{name: "неизвестный режим", cfg: config.Rest{AuthMode: "basic"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAuthenticator(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
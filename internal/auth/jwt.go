@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+
+	"simple-service/internal/config"
+)
+
+// Проверка JWT, выпущенных внешним шлюзом
+
+// jwtClaims - claims токена; роли передаются в claim roles
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles"`
+}
+
+type jwtAuthenticator struct {
+	parser      *jwt.Parser
+	keyFunc     jwt.Keyfunc
+	defaultRole string
+}
+
+// NewJWTAuthenticator - проверка подписи, срока действия, издателя и аудитории токена.
+// Без ролей в токене выдаётся defaultRole
+func NewJWTAuthenticator(cfg config.JWT, defaultRole string) (Authenticator, error) {
+	key, err := jwtKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{cfg.Algorithm}),
+		jwt.WithLeeway(cfg.ClockSkew),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	return &jwtAuthenticator{
+		parser:      jwt.NewParser(opts...),
+		keyFunc:     func(*jwt.Token) (any, error) { return key, nil },
+		defaultRole: defaultRole,
+	}, nil
+}
+
+func (a *jwtAuthenticator) Authenticate(token string) (Principal, error) {
+	var claims jwtClaims
+	_, err := a.parser.ParseWithClaims(token, &claims, a.keyFunc)
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return Principal{}, ErrTokenExpired
+	case err != nil:
+		return Principal{}, ErrTokenInvalid
+	case claims.Subject == "":
+		return Principal{}, ErrNoSubject
+	}
+
+	return Principal{Subject: claims.Subject, Roles: withDefaultRole(claims.Roles, a.defaultRole)}, nil
+}
+
+// jwtKey - ключ проверки подписи для выбранного алгоритма
+func jwtKey(cfg config.JWT) (any, error) {
+	switch cfg.Algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if cfg.Secret == "" {
+			return nil, errors.New("JWT_SECRET is required for HS256")
+		}
+		return []byte(cfg.Secret), nil
+	case jwt.SigningMethodRS256.Alg():
+		if cfg.PublicKeyPath == "" {
+			return nil, errors.New("JWT_PUBLIC_KEY_PATH is required for RS256")
+		}
+		pem, err := os.ReadFile(cfg.PublicKeyPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read JWT public key")
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse JWT public key")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.Algorithm)
+	}
+}
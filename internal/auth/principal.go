@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Данные об авторизованном пользователе, которые миддлваер передаёт дальше по цепочке
+
+const principalKey = "principal"
+
+type principalContextKey struct{}
+
+// Principal - авторизованный пользователь запроса
+type Principal struct {
+	Subject string
+	Roles   []string
+}
+
+// SetPrincipal - сохранение пользователя в locals контекста запроса
+func SetPrincipal(ctx *fiber.Ctx, p Principal) {
+	ctx.Locals(principalKey, p)
+}
+
+// PrincipalFrom - получение пользователя из locals контекста запроса
+func PrincipalFrom(ctx *fiber.Ctx) (Principal, bool) {
+	p, ok := ctx.Locals(principalKey).(Principal)
+	return p, ok && p.Subject != ""
+}
+
+// ContextWithPrincipal - сохранение пользователя в context.Context, используется вне Fiber (gRPC)
+func ContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+// PrincipalFromContext - получение пользователя из context.Context
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(Principal)
+	return p, ok && p.Subject != ""
+}
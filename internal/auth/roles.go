@@ -0,0 +1,36 @@
+package auth
+
+// Роли пользователей и права, которые они дают
+
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Permission - право на группу операций с задачами
+type Permission string
+
+const (
+	PermRead      Permission = "tasks:read"       // Чтение своих задач
+	PermWrite     Permission = "tasks:write"      // Создание, изменение и удаление своих задач
+	PermDeleteAny Permission = "tasks:delete_any" // Удаление чужих задач
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleViewer: {PermRead},
+	RoleEditor: {PermRead, PermWrite},
+	RoleAdmin:  {PermRead, PermWrite, PermDeleteAny},
+}
+
+// Can - есть ли у пользователя право perm хотя бы в одной из его ролей
+func (p Principal) Can(perm Permission) bool {
+	for _, role := range p.Roles {
+		for _, granted := range rolePermissions[role] {
+			if granted == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipalCan(t *testing.T) {
+	tests := []struct {
+		name  string
+		roles []string
+		perm  Permission
+		want  bool
+	}{
+		{name: "viewer читает", roles: []string{RoleViewer}, perm: PermRead, want: true},
+		{name: "viewer не пишет", roles: []string{RoleViewer}, perm: PermWrite, want: false},
+		{name: "editor пишет", roles: []string{RoleEditor}, perm: PermWrite, want: true},
+		{name: "editor не удаляет чужие", roles: []string{RoleEditor}, perm: PermDeleteAny, want: false},
+		{name: "admin удаляет чужие", roles: []string{RoleAdmin}, perm: PermDeleteAny, want: true},
+		{name: "права объединяются", roles: []string{"unknown", RoleViewer, RoleEditor}, perm: PermWrite, want: true},
+		{name: "без ролей", roles: nil, perm: PermRead, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Principal{Subject: "user", Roles: tt.roles}.Can(tt.perm))
+		})
+	}
+}
@@ -1,22 +1,93 @@
 package config
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
 // Общая конфигурация сервиса, тут должны быть все переменные
 
 type AppConfig struct {
-	LogLevel   string
-	Rest       Rest
-	PostgreSQL PostgreSQL
+	LogLevel        string
+	ShutdownDelay   time.Duration `envconfig:"SHUTDOWN_DELAY" default:"5s"`    // Пауза после перевода /readyz в ошибку перед остановкой
+	ShutdownTimeout time.Duration `envconfig:"SHUTDOWN_TIMEOUT" default:"15s"` // Время на завершение текущих запросов
+	AutoMigrate     bool          `envconfig:"AUTO_MIGRATE" default:"false"`   // Применять миграции при запуске
+	Rest            Rest
+	GRPC            GRPC
+	PostgreSQL      PostgreSQL
+	Tracing         Tracing
+	AccessLog       AccessLog
+	RateLimit       RateLimit
+	Idempotency     Idempotency
+	Reminders       Reminders
 }
 
 type Rest struct {
-	ListenAddress string        `envconfig:"PORT" required:"true"`
-	WriteTimeout  time.Duration `envconfig:"WRITE_TIMEOUT" required:"true"`
-	ServerName    string        `envconfig:"SERVER_NAME" required:"true"`
-	Token         string        `envconfig:"TOKEN" required:"true"`
+	ListenAddress string            `envconfig:"PORT" required:"true"`
+	WriteTimeout  time.Duration     `envconfig:"WRITE_TIMEOUT" required:"true"`
+	ServerName    string            `envconfig:"SERVER_NAME" required:"true"`
+	AuthMode      string            `envconfig:"AUTH_MODE" default:"token"`     // token или jwt
+	Tokens        Credentials       `envconfig:"TOKEN"`                         // Обязателен в режиме token
+	Roles         map[string]string `envconfig:"ROLES"`                         // Роли пользователей статических токенов, user:role
+	DefaultRole   string            `envconfig:"DEFAULT_ROLE" default:"editor"` // Роль пользователя, у которого роли не заданы
+	JWT           JWT
+}
+
+// GRPC - gRPC API задач; авторизация та же, что и у REST API
+type GRPC struct {
+	Enabled       bool   `envconfig:"GRPC_ENABLED" default:"true"`
+	ListenAddress string `envconfig:"GRPC_PORT" default:":9090"`
+}
+
+// JWT - настройки проверки JWT в режиме AUTH_MODE=jwt
+type JWT struct {
+	Algorithm     string        `envconfig:"JWT_ALGORITHM" default:"HS256"` // HS256 или RS256
+	Secret        string        `envconfig:"JWT_SECRET"`                    // Ключ подписи для HS256
+	PublicKeyPath string        `envconfig:"JWT_PUBLIC_KEY_PATH"`           // PEM с публичным ключом для RS256
+	Issuer        string        `envconfig:"JWT_ISSUER"`
+	Audience      string        `envconfig:"JWT_AUDIENCE"`
+	ClockSkew     time.Duration `envconfig:"JWT_CLOCK_SKEW" default:"30s"`
+}
+
+// Tracing - настройки экспорта трейсов OpenTelemetry
+type Tracing struct {
+	Exporter    string  `envconfig:"TRACING_EXPORTER" default:"none"`        // none, otlp или stdout
+	Endpoint    string  `envconfig:"OTLP_ENDPOINT" default:"localhost:4318"` // Адрес OTLP/HTTP коллектора
+	Insecure    bool    `envconfig:"OTLP_INSECURE" default:"true"`           // Отправка без TLS
+	ServiceName string  `envconfig:"TRACING_SERVICE_NAME" default:"simple-service"`
+	SampleRatio float64 `envconfig:"TRACING_SAMPLE_RATIO" default:"1"` // Доля записываемых трейсов
+}
+
+// AccessLog - настройки журнала входящих запросов
+type AccessLog struct {
+	SampleRate   float64  `envconfig:"ACCESS_LOG_SAMPLE_RATE" default:"1"`                           // Доля записываемых запросов без ошибок сервера
+	ExcludePaths []string `envconfig:"ACCESS_LOG_EXCLUDE_PATHS" default:"/healthz,/readyz,/metrics"` // Пути, которые не записываются
+}
+
+// RateLimit - ограничение частоты запросов к /v1 для каждого пользователя (или IP, если пользователь неизвестен).
+// Чтение и изменение ограничиваются отдельно
+type RateLimit struct {
+	Enabled    bool    `envconfig:"RATE_LIMIT_ENABLED" default:"true"`
+	ReadRate   float64 `envconfig:"RATE_LIMIT_READ_RPS" default:"50"`    // Запросов на чтение в секунду
+	ReadBurst  int     `envconfig:"RATE_LIMIT_READ_BURST" default:"100"` // Допустимый всплеск запросов на чтение
+	WriteRate  float64 `envconfig:"RATE_LIMIT_WRITE_RPS" default:"10"`   // Запросов на изменение в секунду
+	WriteBurst int     `envconfig:"RATE_LIMIT_WRITE_BURST" default:"20"` // Допустимый всплеск запросов на изменение
+}
+
+// Idempotency - хранение ключей идемпотентности для POST /v1/create_task
+type Idempotency struct {
+	TTL           time.Duration `envconfig:"IDEMPOTENCY_TTL" default:"24h"`           // Время жизни ключа
+	PurgeInterval time.Duration `envconfig:"IDEMPOTENCY_PURGE_INTERVAL" default:"1h"` // Период удаления просроченных ключей
+	LockTimeout   time.Duration `envconfig:"IDEMPOTENCY_LOCK_TIMEOUT" default:"1m"`   // Через сколько резерв без ответа можно занять заново, больше времени запроса
+}
+
+// Reminders - фоновая отправка напоминаний о сроках задач
+type Reminders struct {
+	Enabled   bool          `envconfig:"REMINDERS_ENABLED" default:"true"`
+	Interval  time.Duration `envconfig:"REMINDERS_INTERVAL" default:"30s"`   // Период опроса наступивших напоминаний
+	Lease     time.Duration `envconfig:"REMINDERS_LEASE" default:"5m"`       // Аренда напоминания, на отправку отводится половина
+	BatchSize int           `envconfig:"REMINDERS_BATCH_SIZE" default:"100"` // Сколько напоминаний забирается за один запрос
 }
 
 type PostgreSQL struct {
@@ -30,3 +101,45 @@ type PostgreSQL struct {
 	PoolMaxConnLifetime time.Duration `envconfig:"DB_POOL_MAX_CONN_LIFETIME" default:"180s"`
 	PoolMaxConnIdleTime time.Duration `envconfig:"DB_POOL_MAX_CONN_IDLE_TIME" default:"100s"`
 }
+
+// ConnString - строка подключения к PostgreSQL без параметров пула
+func (c PostgreSQL) ConnString() string {
+	return fmt.Sprintf(
+		`user=%s password=%s host=%s port=%d dbname=%s sslmode=%s`,
+		c.User, c.Password, c.Host, c.Port, c.Name, c.SSLMode,
+	)
+}
+
+// Credential - пользователь и его статический токен
+type Credential struct {
+	Subject string
+	Token   string
+}
+
+// DefaultSubject - пользователь токена, заданного без имени пользователя. Ему при переходе
+// на пользователей были переданы задачи, созданные до их появления
+const DefaultSubject = "default"
+
+// Credentials - список токенов в формате user:token через запятую.
+// У одного пользователя может быть несколько токенов, это позволяет менять секрет без простоя.
+// Токен без двоеточия (прежний формат с одним общим токеном) принадлежит пользователю DefaultSubject
+type Credentials []Credential
+
+// Decode - разбор значения переменной окружения, вызывается envconfig.
+// Сам токен в текст ошибки не попадает, чтобы не оказаться в логах
+func (c *Credentials) Decode(value string) error {
+	var creds Credentials
+	for i, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		subject, token, ok := strings.Cut(item, ":")
+		if !ok {
+			subject, token = DefaultSubject, item
+		}
+		if subject == "" || token == "" {
+			return fmt.Errorf("invalid token #%d, expected user:token", i+1)
+		}
+		creds = append(creds, Credential{Subject: subject, Token: token})
+	}
+	*c = creds
+	return nil
+}
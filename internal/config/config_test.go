@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialsDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Credentials
+		wantErr bool
+	}{
+		{
+			name:  "один токен",
+			value: "admin:123",
+			want:  Credentials{{Subject: "admin", Token: "123"}},
+		},
+		{
+			name:  "несколько токенов с пробелами",
+			value: "alice:old, alice:new,bob:b:c",
+			want: Credentials{
+				{Subject: "alice", Token: "old"},
+				{Subject: "alice", Token: "new"},
+				{Subject: "bob", Token: "b:c"},
+			},
+		},
+		{
+			name:  "токен без пользователя в прежнем формате",
+			value: "123",
+			want:  Credentials{{Subject: DefaultSubject, Token: "123"}},
+		},
+		{name: "пустой пользователь", value: ":123", wantErr: true},
+		{name: "пустой токен", value: "admin:", wantErr: true},
+		{name: "пустое значение", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var creds Credentials
+			err := creds.Decode(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.NotContains(t, err.Error(), "123")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, creds)
+		})
+	}
+}
@@ -10,13 +10,25 @@ const (
 	FieldBadFormat     = "FIELD_BADFORMAT"
 	FieldIncorrect     = "FIELD_INCORRECT"
 	ServiceUnavailable = "SERVICE_UNAVAILABLE"
+	InvalidTransition  = "INVALID_TRANSITION"
+	TaskNotFound       = "TASK_NOT_FOUND"
+	Unauthorized       = "UNAUTHORIZED"
+	TokenExpired       = "TOKEN_EXPIRED"
+	TokenInvalid       = "TOKEN_INVALID"
+	Forbidden          = "FORBIDDEN"
+	RateLimited        = "RATE_LIMITED"
+	IdempotencyReused  = "IDEMPOTENCY_KEY_REUSED"
+	IdempotencyPending = "IDEMPOTENCY_IN_PROGRESS"
+	VersionMismatch    = "VERSION_MISMATCH"
+	PreconditionNeeded = "PRECONDITION_REQUIRED"
 	InternalError      = "Service is currently unavailable. Please try again later."
 )
 
 type Response struct {
-	Status string `json:"status"`
-	Error  *Error `json:"error,omitempty"`
-	Data   any    `json:"data,omitempty"`
+	Status     string `json:"status"`
+	Error      *Error `json:"error,omitempty"`
+	Data       any    `json:"data,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"` // Курсор следующей страницы для списков
 }
 
 type Error struct {
@@ -34,6 +46,46 @@ func BadResponseError(ctx *fiber.Ctx, code, desc string) error {
 	})
 }
 
+func UnauthorizedError(ctx *fiber.Ctx, code, desc string) error {
+	return ctx.Status(fiber.StatusUnauthorized).JSON(Response{
+		Status: "error",
+		Error: &Error{
+			Code: code,
+			Desc: desc,
+		},
+	})
+}
+
+func ForbiddenError(ctx *fiber.Ctx, code, desc string) error {
+	return ctx.Status(fiber.StatusForbidden).JSON(Response{
+		Status: "error",
+		Error: &Error{
+			Code: code,
+			Desc: desc,
+		},
+	})
+}
+
+func NotFoundError(ctx *fiber.Ctx, code, desc string) error {
+	return ctx.Status(fiber.StatusNotFound).JSON(Response{
+		Status: "error",
+		Error: &Error{
+			Code: code,
+			Desc: desc,
+		},
+	})
+}
+
+func UnavailableError(ctx *fiber.Ctx, code, desc string) error {
+	return ctx.Status(fiber.StatusServiceUnavailable).JSON(Response{
+		Status: "error",
+		Error: &Error{
+			Code: code,
+			Desc: desc,
+		},
+	})
+}
+
 func InternalServerError(ctx *fiber.Ctx) error {
 	return ctx.Status(fiber.StatusInternalServerError).JSON(Response{
 		Status: "error",
@@ -43,3 +95,43 @@ func InternalServerError(ctx *fiber.Ctx) error {
 		},
 	})
 }
+
+func ConflictError(ctx *fiber.Ctx, code, desc string) error {
+	return ctx.Status(fiber.StatusConflict).JSON(Response{
+		Status: "error",
+		Error: &Error{
+			Code: code,
+			Desc: desc,
+		},
+	})
+}
+
+func TooManyRequestsError(ctx *fiber.Ctx, code, desc string) error {
+	return ctx.Status(fiber.StatusTooManyRequests).JSON(Response{
+		Status: "error",
+		Error: &Error{
+			Code: code,
+			Desc: desc,
+		},
+	})
+}
+
+func PreconditionFailedError(ctx *fiber.Ctx, code, desc string) error {
+	return ctx.Status(fiber.StatusPreconditionFailed).JSON(Response{
+		Status: "error",
+		Error: &Error{
+			Code: code,
+			Desc: desc,
+		},
+	})
+}
+
+func PreconditionRequiredError(ctx *fiber.Ctx, code, desc string) error {
+	return ctx.Status(fiber.StatusPreconditionRequired).JSON(Response{
+		Status: "error",
+		Error: &Error{
+			Code: code,
+			Desc: desc,
+		},
+	})
+}
@@ -0,0 +1,109 @@
+package grpcapi
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"simple-service/internal/repo"
+	"simple-service/internal/service"
+	taskv1 "simple-service/pkg/pb/task/v1"
+)
+
+// Преобразование сообщений protobuf в типы сервиса и обратно
+
+var statusToProto = map[repo.TaskStatus]taskv1.TaskStatus{
+	repo.StatusNew:        taskv1.TaskStatus_TASK_STATUS_NEW,
+	repo.StatusInProgress: taskv1.TaskStatus_TASK_STATUS_IN_PROGRESS,
+	repo.StatusDone:       taskv1.TaskStatus_TASK_STATUS_DONE,
+}
+
+// statusFromProto - статус задачи для запроса сервиса, UNSPECIFIED превращается в пустую строку
+func statusFromProto(s taskv1.TaskStatus) string {
+	for status, value := range statusToProto {
+		if value == s {
+			return string(status)
+		}
+	}
+	return ""
+}
+
+// taskToProto - задача в сообщении protobuf
+func taskToProto(task *repo.Task) *taskv1.Task {
+	return &taskv1.Task{
+		Id:          int64(task.ID),
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      statusToProto[task.Status],
+		CreatedAt:   timestamppb.New(task.CreatedAt),
+		UpdatedAt:   timestamppb.New(task.UpdatedAt),
+		Version:     int64(task.Version),
+		DueAt:       timestampToProto(task.DueAt),
+		RemindAt:    timestampToProto(task.RemindAt),
+	}
+}
+
+// timestampToProto - необязательное время в сообщении protobuf
+func timestampToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// timestampPatch - изменение необязательного времени: новое значение, очистка или без изменений
+func timestampPatch(name string, ts *timestamppb.Timestamp, clear bool) (repo.NullableTime, error) {
+	switch {
+	case ts != nil && clear:
+		return repo.NullableTime{}, status.Errorf(codes.InvalidArgument, "%s and clear_%s are mutually exclusive", name, name)
+	case clear:
+		return repo.NullableTime{Set: true}, nil
+	case ts != nil:
+		return repo.NullableTime{Time: timestampFromProto(ts), Set: true}, nil
+	}
+	return repo.NullableTime{}, nil
+}
+
+// timestampFromProto - необязательное время из сообщения protobuf, nil - поле не задано
+func timestampFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// listRequest - параметры списка задач в том виде, в каком их принимает REST API
+func listRequest(req *taskv1.ListTasksRequest) service.ListTasksRequest {
+	list := service.ListTasksRequest{
+		Limit:   int(req.GetLimit()),
+		Sort:    req.GetSort(),
+		Status:  statusFromProto(req.GetStatus()),
+		Cursor:  req.GetCursor(),
+		Overdue: req.GetOverdue(),
+	}
+	if req.CreatedFrom != nil {
+		list.CreatedFrom = req.GetCreatedFrom().AsTime().Format(time.RFC3339Nano)
+	}
+	if req.CreatedTo != nil {
+		list.CreatedTo = req.GetCreatedTo().AsTime().Format(time.RFC3339Nano)
+	}
+	return list
+}
+
+// expectedVersion - ожидаемая версия задачи для сервиса, nil отключает проверку.
+// Как и REST API, без версии задача изменяется только явным force
+func expectedVersion(v *int64, force bool) (*int, error) {
+	switch {
+	case v != nil && force:
+		return nil, status.Error(codes.InvalidArgument, "expected_version and force are mutually exclusive")
+	case force:
+		return nil, nil
+	case v == nil:
+		return nil, status.Error(codes.FailedPrecondition, "expected_version is required, set force to skip the version check")
+	}
+	version := int(*v)
+	return &version, nil
+}
@@ -0,0 +1,132 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"simple-service/internal/auth"
+	logging "simple-service/internal/logger"
+	"simple-service/internal/requestid"
+)
+
+// Перехватчики gRPC - аналоги миддлваеров REST API
+
+const (
+	// MetadataRequestID - ключ метаданных с идентификатором запроса
+	MetadataRequestID = "x-request-id"
+
+	metadataAuthorization = "authorization"
+	bearerPrefix          = "Bearer "
+)
+
+// callInfo - данные вызова, которые внутренние перехватчики передают журналу вызовов
+type callInfo struct {
+	principal string
+}
+
+type callInfoKey struct{}
+
+// RequestID - берёт x-request-id из метаданных запроса или генерирует новый, возвращает его
+// в заголовке ответа и сохраняет в контексте логгер запроса с request_id и методом
+func RequestID(log *zap.SugaredLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		requestID := requestid.FromClient(incoming(ctx, MetadataRequestID))
+		_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataRequestID, requestID))
+
+		ctx = logging.ContextWithLogger(ctx, log.With(
+			"request_id", requestID,
+			"method", info.FullMethod,
+		))
+		return handler(ctx, req)
+	}
+}
+
+// AccessLog - одна запись на вызов с методом, кодом статуса, временем обработки, адресом клиента
+// и пользователем. Вызовы, завершившиеся ошибкой сервера, записываются с уровнем Error
+func AccessLog(log *zap.SugaredLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		call := &callInfo{}
+		resp, err := handler(context.WithValue(ctx, callInfoKey{}, call), req)
+
+		code := status.Code(err)
+		fields := []any{
+			"code", code.String(),
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			fields = append(fields, "ip", p.Addr.String())
+		}
+		if call.principal != "" {
+			fields = append(fields, "principal", call.principal)
+		}
+
+		reqLog := logging.LoggerFromContext(ctx, log)
+		if serverError(code) {
+			reqLog.Errorw("gRPC request", fields...)
+		} else {
+			reqLog.Infow("gRPC request", fields...)
+		}
+		return resp, err
+	}
+}
+
+// Auth - проверка метаданных authorization: Bearer <token> и прав пользователя на вызов метода.
+// Пользователь сохраняется в контексте, логгер запроса дополняется его именем
+func Auth(authenticator auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		header := incoming(ctx, metadataAuthorization)
+		if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+
+		principal, err := authenticator.Authenticate(header[len(bearerPrefix):])
+		switch {
+		case errors.Is(err, auth.ErrUnknownToken):
+			return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+		case err != nil:
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		if call, ok := ctx.Value(callInfoKey{}).(*callInfo); ok {
+			call.principal = principal.Subject
+		}
+
+		perm, ok := methodPermissions[info.FullMethod]
+		if !ok || !principal.Can(perm) {
+			return nil, status.Error(codes.PermissionDenied, "insufficient permissions")
+		}
+
+		ctx = auth.ContextWithPrincipal(ctx, principal)
+		if log := logging.LoggerFromContext(ctx, nil); log != nil {
+			ctx = logging.ContextWithLogger(ctx, log.With("principal", principal.Subject))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// incoming - первое значение ключа из метаданных запроса
+func incoming(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// serverError - код означает сбой сервиса, а не ошибку клиента
+func serverError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		return true
+	}
+	return false
+}
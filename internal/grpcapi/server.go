@@ -0,0 +1,182 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"simple-service/internal/auth"
+	logging "simple-service/internal/logger"
+	"simple-service/internal/service"
+	"simple-service/internal/tracing"
+	taskv1 "simple-service/pkg/pb/task/v1"
+)
+
+// gRPC API задач. Вызовы переводятся в методы того же TaskService, что и у REST API,
+// доменные ошибки - в коды статуса gRPC
+
+// methodPermissions - права, которые нужны для вызова метода. Методы без записи отклоняются
+var methodPermissions = map[string]auth.Permission{
+	taskv1.TaskService_CreateTask_FullMethodName:     auth.PermWrite,
+	taskv1.TaskService_GetTask_FullMethodName:        auth.PermRead,
+	taskv1.TaskService_ListTasks_FullMethodName:      auth.PermRead,
+	taskv1.TaskService_UpdateTask_FullMethodName:     auth.PermWrite,
+	taskv1.TaskService_DeleteTask_FullMethodName:     auth.PermWrite,
+	taskv1.TaskService_TransitionTask_FullMethodName: auth.PermWrite,
+}
+
+// NewServer - gRPC-сервер с сервисом задач и перехватчиками идентификатора запроса,
+// журнала вызовов и авторизации
+func NewServer(tasks service.TaskService, authenticator auth.Authenticator, log *zap.SugaredLogger) *grpc.Server {
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		RequestID(log),
+		AccessLog(log),
+		Auth(authenticator),
+	))
+	taskv1.RegisterTaskServiceServer(srv, NewTaskServer(tasks, log))
+	return srv
+}
+
+// TaskServer - реализация taskv1.TaskServiceServer поверх сервиса задач
+type TaskServer struct {
+	taskv1.UnimplementedTaskServiceServer
+
+	tasks service.TaskService
+	log   *zap.SugaredLogger
+}
+
+// NewTaskServer - конструктор gRPC-обработчиков задач
+func NewTaskServer(tasks service.TaskService, log *zap.SugaredLogger) *TaskServer {
+	return &TaskServer{tasks: tasks, log: log}
+}
+
+// CreateTask - создание задачи
+func (s *TaskServer) CreateTask(ctx context.Context, req *taskv1.CreateTaskRequest) (*taskv1.CreateTaskResponse, error) {
+	principal, _ := auth.PrincipalFromContext(ctx)
+
+	taskID, err := s.tasks.CreateTask(ctx, principal, service.TaskRequest{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		DueAt:       timestampFromProto(req.GetDueAt()),
+		RemindAt:    timestampFromProto(req.GetRemindAt()),
+	})
+	if err != nil {
+		return nil, s.fail(ctx, err, "Failed to insert task")
+	}
+	return &taskv1.CreateTaskResponse{Id: int64(taskID)}, nil
+}
+
+// GetTask - получение задачи по id
+func (s *TaskServer) GetTask(ctx context.Context, req *taskv1.GetTaskRequest) (*taskv1.GetTaskResponse, error) {
+	principal, _ := auth.PrincipalFromContext(ctx)
+
+	task, err := s.tasks.GetTask(ctx, principal, int(req.GetId()))
+	if err != nil {
+		return nil, s.fail(ctx, err, "Failed to get task")
+	}
+	return &taskv1.GetTaskResponse{Task: taskToProto(task)}, nil
+}
+
+// ListTasks - страница задач с учётом фильтров, сортировки и курсора
+func (s *TaskServer) ListTasks(ctx context.Context, req *taskv1.ListTasksRequest) (*taskv1.ListTasksResponse, error) {
+	principal, _ := auth.PrincipalFromContext(ctx)
+
+	list, err := s.tasks.ListTasks(ctx, principal, listRequest(req))
+	if err != nil {
+		return nil, s.fail(ctx, err, "Failed to list tasks")
+	}
+
+	resp := &taskv1.ListTasksResponse{
+		Tasks:      make([]*taskv1.Task, 0, len(list.Tasks)),
+		NextCursor: list.NextCursor,
+	}
+	for i := range list.Tasks {
+		resp.Tasks = append(resp.Tasks, taskToProto(&list.Tasks[i]))
+	}
+	return resp, nil
+}
+
+// UpdateTask - изменение заданных полей задачи, возвращает новую версию
+func (s *TaskServer) UpdateTask(ctx context.Context, req *taskv1.UpdateTaskRequest) (*taskv1.UpdateTaskResponse, error) {
+	principal, _ := auth.PrincipalFromContext(ctx)
+	expected, err := expectedVersion(req.ExpectedVersion, req.GetForce())
+	if err != nil {
+		return nil, err
+	}
+	dueAt, err := timestampPatch("due_at", req.GetDueAt(), req.GetClearDueAt())
+	if err != nil {
+		return nil, err
+	}
+	remindAt, err := timestampPatch("remind_at", req.GetRemindAt(), req.GetClearRemindAt())
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.tasks.PatchTask(ctx, principal, int(req.GetId()), service.TaskPatchRequest{
+		Title:       req.Title,
+		Description: req.Description,
+		DueAt:       dueAt,
+		RemindAt:    remindAt,
+	}, expected)
+	if err != nil {
+		return nil, s.fail(ctx, err, "Failed to patch task")
+	}
+	return &taskv1.UpdateTaskResponse{Version: int64(version)}, nil
+}
+
+// DeleteTask - удаление задачи
+func (s *TaskServer) DeleteTask(ctx context.Context, req *taskv1.DeleteTaskRequest) (*taskv1.DeleteTaskResponse, error) {
+	principal, _ := auth.PrincipalFromContext(ctx)
+
+	expected, err := expectedVersion(req.ExpectedVersion, req.GetForce())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tasks.DeleteTask(ctx, principal, int(req.GetId()), expected); err != nil {
+		return nil, s.fail(ctx, err, "Failed to delete task")
+	}
+	return &taskv1.DeleteTaskResponse{}, nil
+}
+
+// TransitionTask - смена статуса задачи по правилам переходов
+func (s *TaskServer) TransitionTask(ctx context.Context, req *taskv1.TransitionTaskRequest) (*taskv1.TransitionTaskResponse, error) {
+	principal, _ := auth.PrincipalFromContext(ctx)
+
+	to, err := s.tasks.TransitionTask(ctx, principal, int(req.GetId()), service.TransitionRequest{
+		Status: statusFromProto(req.GetStatus()),
+		Reopen: req.GetReopen(),
+	})
+	if err != nil {
+		return nil, s.fail(ctx, err, "Failed to update task status")
+	}
+	return &taskv1.TransitionTaskResponse{Status: statusToProto[to]}, nil
+}
+
+// fail - перевод доменной ошибки сервиса в статус gRPC, прочие ошибки логируются и скрываются от клиента
+func (s *TaskServer) fail(ctx context.Context, err error, msg string) error {
+	var validationErr *service.ValidationError
+	var transitionErr *service.TransitionError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return status.Error(codes.InvalidArgument, validationErr.Error())
+	case errors.Is(err, service.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, "unauthenticated")
+	case errors.Is(err, service.ErrTaskNotFound):
+		return status.Error(codes.NotFound, "task not found")
+	case errors.Is(err, service.ErrVersionMismatch):
+		return status.Error(codes.FailedPrecondition, "task was modified, reload it and retry")
+	case errors.As(err, &transitionErr):
+		return status.Error(codes.FailedPrecondition, transitionErr.Error())
+	case errors.Is(err, service.ErrStatusChanged):
+		return status.Error(codes.Aborted, service.ErrStatusChanged.Error())
+	}
+
+	logging.LoggerFromContext(ctx, s.log).With(tracing.LogFields(ctx)...).Errorw(msg, "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,299 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/config"
+	"simple-service/internal/repo"
+	"simple-service/internal/repo/mocks"
+	"simple-service/internal/service"
+	taskv1 "simple-service/pkg/pb/task/v1"
+)
+
+const testOwner = "user"
+
+// newTestClient - клиент gRPC-сервера в памяти с пользователями user (editor) и guest (viewer)
+func newTestClient(t *testing.T, repository repo.Repository) (taskv1.TaskServiceClient, *observer.ObservedLogs) {
+	core, logs := observer.New(zap.InfoLevel)
+	authenticator := auth.NewTokenAuthenticator(config.Credentials{
+		{Subject: testOwner, Token: "editor-token"},
+		{Subject: "guest", Token: "viewer-token"},
+	}, map[string]string{"guest": auth.RoleViewer}, auth.RoleEditor)
+
+	listener := bufconn.Listen(1 << 20)
+	srv := NewServer(service.NewTaskService(repository), authenticator, zap.New(core).Sugar())
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return taskv1.NewTaskServiceClient(conn), logs
+}
+
+// withToken - контекст вызова с токеном в метаданных authorization
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestAuth(t *testing.T) {
+	mockRepo := new(mocks.Repository)
+	client, _ := newTestClient(t, mockRepo)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode codes.Code
+	}{
+		{name: "Без токена", ctx: context.Background(), wantCode: codes.Unauthenticated},
+		{name: "Неизвестный токен", ctx: withToken("wrong"), wantCode: codes.Unauthenticated},
+		{name: "Роль viewer не может создавать задачи", ctx: withToken("viewer-token"), wantCode: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreateTask(tt.ctx, &taskv1.CreateTaskRequest{Title: "Task"})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	t.Run("Роль viewer читает свои задачи", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, "guest", 1).Return(&repo.Task{ID: 1, Status: repo.StatusNew}, nil).Once()
+
+		_, err := client.GetTask(withToken("viewer-token"), &taskv1.GetTaskRequest{Id: 1})
+		assert.NoError(t, err)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRequestIDAndAccessLog(t *testing.T) {
+	mockRepo := new(mocks.Repository)
+	client, logs := newTestClient(t, mockRepo)
+
+	mockRepo.On("CreateTask", mock.Anything, testOwner, repo.Task{Title: "Task"}).Return(7, nil).Once()
+
+	t.Run("Идентификатор клиента возвращается в заголовке ответа", func(t *testing.T) {
+		logs.TakeAll()
+		ctx := metadata.AppendToOutgoingContext(withToken("editor-token"), MetadataRequestID, "req-42")
+
+		var header metadata.MD
+		resp, err := client.CreateTask(ctx, &taskv1.CreateTaskRequest{Title: "Task"}, grpc.Header(&header))
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), resp.GetId())
+		assert.Equal(t, []string{"req-42"}, header.Get(MetadataRequestID))
+
+		entries := logs.FilterMessage("gRPC request").TakeAll()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "req-42", fields["request_id"])
+		assert.Equal(t, taskv1.TaskService_CreateTask_FullMethodName, fields["method"])
+		assert.Equal(t, codes.OK.String(), fields["code"])
+		assert.Equal(t, testOwner, fields["principal"])
+	})
+
+	t.Run("Идентификатор генерируется, если его нет", func(t *testing.T) {
+		var header metadata.MD
+		_, err := client.GetTask(context.Background(), &taskv1.GetTaskRequest{Id: 1}, grpc.Header(&header))
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		require.Len(t, header.Get(MetadataRequestID), 1)
+		assert.Len(t, header.Get(MetadataRequestID)[0], 32)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskServer(t *testing.T) {
+	mockRepo := new(mocks.Repository)
+	client, _ := newTestClient(t, mockRepo)
+	ctx := withToken("editor-token")
+
+	t.Run("Получение задачи", func(t *testing.T) {
+		created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		mockRepo.On("GetTask", mock.Anything, testOwner, 1).Return(&repo.Task{
+			ID: 1, Title: "Task", Status: repo.StatusInProgress, CreatedAt: created, UpdatedAt: created, Version: 3,
+		}, nil).Once()
+
+		resp, err := client.GetTask(ctx, &taskv1.GetTaskRequest{Id: 1})
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(&taskv1.Task{
+			Id:        1,
+			Title:     "Task",
+			Status:    taskv1.TaskStatus_TASK_STATUS_IN_PROGRESS,
+			CreatedAt: timestamppb.New(created),
+			UpdatedAt: timestamppb.New(created),
+			Version:   3,
+		}, resp.GetTask()))
+	})
+
+	t.Run("Задача не найдена", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 2).Return(nil, repo.ErrTaskNotFound).Once()
+
+		_, err := client.GetTask(ctx, &taskv1.GetTaskRequest{Id: 2})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Список задач с фильтрами", func(t *testing.T) {
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		done := repo.StatusDone
+		mockRepo.On("ListTasks", mock.Anything, testOwner, repo.TaskFilter{
+			Status:      &done,
+			CreatedFrom: &from,
+			Sort:        repo.SortTitle,
+			Limit:       5,
+		}).Return(&repo.TaskPage{Tasks: []repo.Task{{ID: 1, Status: repo.StatusDone}}}, nil).Once()
+
+		resp, err := client.ListTasks(ctx, &taskv1.ListTasksRequest{
+			Limit:       5,
+			Sort:        "title",
+			Status:      taskv1.TaskStatus_TASK_STATUS_DONE,
+			CreatedFrom: timestamppb.New(from),
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.GetTasks(), 1)
+		assert.Equal(t, taskv1.TaskStatus_TASK_STATUS_DONE, resp.GetTasks()[0].GetStatus())
+		assert.Empty(t, resp.GetNextCursor())
+	})
+
+	t.Run("Создание задачи со сроком и напоминанием", func(t *testing.T) {
+		due := time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+		remind := due.Add(-time.Hour)
+		mockRepo.On("CreateTask", mock.Anything, testOwner, repo.Task{Title: "Task", DueAt: &due, RemindAt: &remind}).
+			Return(5, nil).Once()
+
+		resp, err := client.CreateTask(ctx, &taskv1.CreateTaskRequest{
+			Title:    "Task",
+			DueAt:    timestamppb.New(due),
+			RemindAt: timestamppb.New(remind),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), resp.GetId())
+	})
+
+	t.Run("Напоминание позже срока", func(t *testing.T) {
+		due := time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+
+		_, err := client.CreateTask(ctx, &taskv1.CreateTaskRequest{
+			Title:    "Task",
+			DueAt:    timestamppb.New(due),
+			RemindAt: timestamppb.New(due.Add(time.Hour)),
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Некорректный лимит", func(t *testing.T) {
+		_, err := client.ListTasks(ctx, &taskv1.ListTasksRequest{Limit: 1000})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Изменение заданных полей с проверкой версии", func(t *testing.T) {
+		title := "Patched"
+		version := 3
+		mockRepo.On("PatchTask", mock.Anything, testOwner, 1, repo.TaskPatch{Title: &title}, &version).Return(4, nil).Once()
+
+		resp, err := client.UpdateTask(ctx, &taskv1.UpdateTaskRequest{Id: 1, Title: proto.String(title), ExpectedVersion: proto.Int64(3)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), resp.GetVersion())
+	})
+
+	t.Run("Очистка срока и напоминания", func(t *testing.T) {
+		version := 2
+		mockRepo.On("PatchTask", mock.Anything, testOwner, 1, repo.TaskPatch{
+			DueAt:    repo.NullableTime{Set: true},
+			RemindAt: repo.NullableTime{Set: true},
+		}, &version).Return(3, nil).Once()
+
+		_, err := client.UpdateTask(ctx, &taskv1.UpdateTaskRequest{
+			Id: 1, ExpectedVersion: proto.Int64(2), ClearDueAt: true, ClearRemindAt: true,
+		})
+		require.NoError(t, err)
+
+		_, err = client.UpdateTask(ctx, &taskv1.UpdateTaskRequest{
+			Id: 1, ExpectedVersion: proto.Int64(2), DueAt: timestamppb.Now(), ClearDueAt: true,
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Без ожидаемой версии", func(t *testing.T) {
+		_, err := client.UpdateTask(ctx, &taskv1.UpdateTaskRequest{Id: 1, Title: proto.String("Patched")})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		_, err = client.DeleteTask(ctx, &taskv1.DeleteTaskRequest{Id: 1})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("Удаление без проверки версии", func(t *testing.T) {
+		mockRepo.On("DeleteTask", mock.Anything, testOwner, 4, (*int)(nil)).Return(nil).Once()
+
+		_, err := client.DeleteTask(ctx, &taskv1.DeleteTaskRequest{Id: 4, Force: true})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Версия и force вместе", func(t *testing.T) {
+		_, err := client.DeleteTask(ctx, &taskv1.DeleteTaskRequest{Id: 1, ExpectedVersion: proto.Int64(1), Force: true})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Версия задачи изменилась", func(t *testing.T) {
+		version := 1
+		mockRepo.On("DeleteTask", mock.Anything, testOwner, 1, &version).Return(repo.ErrVersionMismatch).Once()
+
+		_, err := client.DeleteTask(ctx, &taskv1.DeleteTaskRequest{Id: 1, ExpectedVersion: proto.Int64(1)})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("Смена статуса", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 3).Return(&repo.Task{Status: repo.StatusNew}, nil).Once()
+		mockRepo.On("UpdateTaskStatus", mock.Anything, testOwner, 3, repo.StatusNew, repo.StatusDone).Return(nil).Once()
+
+		resp, err := client.TransitionTask(ctx, &taskv1.TransitionTaskRequest{Id: 3, Status: taskv1.TaskStatus_TASK_STATUS_DONE})
+		require.NoError(t, err)
+		assert.Equal(t, taskv1.TaskStatus_TASK_STATUS_DONE, resp.GetStatus())
+	})
+
+	t.Run("Недопустимый переход", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 4).Return(&repo.Task{Status: repo.StatusDone}, nil).Once()
+
+		_, err := client.TransitionTask(ctx, &taskv1.TransitionTaskRequest{Id: 4, Status: taskv1.TaskStatus_TASK_STATUS_NEW})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("Статус не указан", func(t *testing.T) {
+		_, err := client.TransitionTask(ctx, &taskv1.TransitionTaskRequest{Id: 4})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Ошибка БД скрывается от клиента", func(t *testing.T) {
+		mockRepo.On("CreateTask", mock.Anything, testOwner, repo.Task{Title: "Task"}).Return(0, assert.AnError).Once()
+
+		_, err := client.CreateTask(ctx, &taskv1.CreateTaskRequest{Title: "Task"})
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Equal(t, "internal error", status.Convert(err).Message())
+	})
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Логгер запроса, который миддлваеры дополняют данными о запросе и передают дальше по цепочке
+
+const loggerKey = "logger"
+
+type loggerContextKey struct{}
+
+// SetRequestLogger - сохранение логгера запроса в locals контекста запроса
+func SetRequestLogger(ctx *fiber.Ctx, log *zap.SugaredLogger) {
+	ctx.Locals(loggerKey, log)
+}
+
+// FromContext - логгер запроса из locals контекста запроса.
+// Если логгер не сохранён (например, миддлваер не подключён), возвращается fallback
+func FromContext(ctx *fiber.Ctx, fallback *zap.SugaredLogger) *zap.SugaredLogger {
+	if log, ok := ctx.Locals(loggerKey).(*zap.SugaredLogger); ok && log != nil {
+		return log
+	}
+	return fallback
+}
+
+// ContextWithLogger - сохранение логгера запроса в context.Context, используется вне Fiber (gRPC)
+func ContextWithLogger(ctx context.Context, log *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, log)
+}
+
+// LoggerFromContext - логгер запроса из context.Context или fallback, если логгер не сохранён
+func LoggerFromContext(ctx context.Context, fallback *zap.SugaredLogger) *zap.SugaredLogger {
+	if log, ok := ctx.Value(loggerContextKey{}).(*zap.SugaredLogger); ok && log != nil {
+		return log
+	}
+	return fallback
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Метрики сервиса для Prometheus, регистрируются в реестре по умолчанию
+
+const namespace = "simple_service"
+
+var (
+	// HTTPRequests - количество обработанных HTTP-запросов
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of handled HTTP requests.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPDuration - время обработки HTTP-запросов
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// TasksCreated - количество созданных задач
+	TasksCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_created_total",
+		Help:      "Number of created tasks.",
+	})
+
+	// TasksCompleted - количество задач, переведённых в статус done
+	TasksCompleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_completed_total",
+		Help:      "Number of tasks moved to the done status.",
+	})
+
+	// RemindersSent - количество отправленных напоминаний о сроках задач
+	RemindersSent = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reminders_sent_total",
+		Help:      "Number of sent task reminders.",
+	})
+)
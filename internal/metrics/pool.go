@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Статистика пула соединений PostgreSQL, снимается в момент опроса /metrics
+
+var (
+	poolAcquiredConns = prometheus.NewDesc(
+		namespace+"_db_pool_acquired_conns", "Number of currently acquired connections.", nil, nil)
+	poolIdleConns = prometheus.NewDesc(
+		namespace+"_db_pool_idle_conns", "Number of currently idle connections.", nil, nil)
+	poolTotalConns = prometheus.NewDesc(
+		namespace+"_db_pool_total_conns", "Total number of connections in the pool.", nil, nil)
+	poolMaxConns = prometheus.NewDesc(
+		namespace+"_db_pool_max_conns", "Maximum size of the pool.", nil, nil)
+	poolAcquireCount = prometheus.NewDesc(
+		namespace+"_db_pool_acquire_total", "Number of successful acquires from the pool.", nil, nil)
+	poolEmptyAcquireCount = prometheus.NewDesc(
+		namespace+"_db_pool_empty_acquire_total", "Number of acquires that had to wait for a connection.", nil, nil)
+	poolAcquireDuration = prometheus.NewDesc(
+		namespace+"_db_pool_acquire_wait_seconds_total", "Total time spent waiting for a connection.", nil, nil)
+)
+
+// PoolCollector - сборщик статистики пула pgxpool
+type PoolCollector struct {
+	stat func() *pgxpool.Stat
+}
+
+// NewPoolCollector - сборщик, который вызывает stat при каждом опросе
+func NewPoolCollector(stat func() *pgxpool.Stat) *PoolCollector {
+	return &PoolCollector{stat: stat}
+}
+
+// Describe - описание метрик сборщика
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConns
+	ch <- poolIdleConns
+	ch <- poolTotalConns
+	ch <- poolMaxConns
+	ch <- poolAcquireCount
+	ch <- poolEmptyAcquireCount
+	ch <- poolAcquireDuration
+}
+
+// Collect - текущие значения статистики пула
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredConns, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleConns, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalConns, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxConns, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireCount, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquireCount, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireDuration, prometheus.CounterValue, s.AcquireDuration().Seconds())
+}
@@ -0,0 +1,225 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+
+	"simple-service/internal/config"
+)
+
+// Применение миграций схемы БД. Версии применённых миграций хранятся в schema_migrations,
+// а advisory lock не даёт нескольким репликам мигрировать одновременно
+
+const (
+	// lockKey - ключ advisory lock, общий для всех реплик сервиса
+	lockKey = 7_318_944_201
+
+	createTableQuery = `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT now()
+	)`
+	appliedQuery = `SELECT version, applied_at FROM schema_migrations ORDER BY version`
+	insertQuery  = `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`
+	deleteQuery  = `DELETE FROM schema_migrations WHERE version=($1)`
+	lockQuery    = `SELECT pg_advisory_lock($1)`
+	unlockQuery  = `SELECT pg_advisory_unlock($1)`
+)
+
+// Status - состояние одной миграции
+type Status struct {
+	Migration
+	AppliedAt *time.Time // nil, если миграция не применена
+}
+
+// Migrator - применение и откат миграций через отдельное соединение с БД
+type Migrator struct {
+	conn       *pgx.Conn
+	migrations []Migration
+}
+
+// Connect - подключение к БД и чтение миграций из fsys
+func Connect(ctx context.Context, cfg config.PostgreSQL, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := pgx.Connect(ctx, cfg.ConnString())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to PostgreSQL")
+	}
+
+	return &Migrator{conn: conn, migrations: migrations}, nil
+}
+
+// Close - закрытие соединения
+func (m *Migrator) Close(ctx context.Context) error {
+	return m.conn.Close(ctx)
+}
+
+// Up - применение всех ещё не применённых миграций по возрастанию версии
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(applied map[int]time.Time) error {
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, mig.Up, insertQuery, mig.Version, mig.Name); err != nil {
+				return errors.Wrapf(err, "failed to apply migration %d_%s", mig.Version, mig.Name)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Baseline - отметка миграций до version включительно как применённых без выполнения их SQL.
+// Нужна для БД, схема которой создана вручную до появления schema_migrations
+func (m *Migrator) Baseline(ctx context.Context, version int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(applied map[int]time.Time) error {
+		marked, err := baseline(m.migrations, applied, version)
+		if err != nil {
+			return err
+		}
+		// Отметки пишутся одной транзакцией, чтобы ошибка не оставила часть миграций отмеченной
+		err = pgx.BeginFunc(ctx, m.conn, func(tx pgx.Tx) error {
+			for _, mig := range marked {
+				if _, err := tx.Exec(ctx, insertQuery, mig.Version, mig.Name); err != nil {
+					return errors.Wrapf(err, "failed to mark migration %d_%s as applied", mig.Version, mig.Name)
+				}
+			}
+			return nil
+		})
+		if err == nil {
+			done = marked
+		}
+		return err
+	})
+	return done, err
+}
+
+// baseline - ещё не применённые миграции до version включительно; version должна быть известной миграцией
+func baseline(migrations []Migration, applied map[int]time.Time, version int) ([]Migration, error) {
+	known := false
+	var marked []Migration
+	for _, mig := range migrations {
+		if mig.Version > version {
+			break
+		}
+		known = known || mig.Version == version
+		if _, ok := applied[mig.Version]; !ok {
+			marked = append(marked, mig)
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown migration version %d", version)
+	}
+	return marked, nil
+}
+
+// Down - откат steps последних применённых миграций
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(applied map[int]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", mig.Version, mig.Name)
+			}
+			if err := m.apply(ctx, mig.Down, deleteQuery, mig.Version); err != nil {
+				return errors.Wrapf(err, "failed to roll back migration %d_%s", mig.Version, mig.Name)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status - все известные миграции с отметкой о применении
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if _, err := m.conn.Exec(ctx, createTableQuery); err != nil {
+		return nil, errors.Wrap(err, "failed to create schema_migrations")
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := Status{Migration: mig}
+		if at, ok := applied[mig.Version]; ok {
+			st.AppliedAt = &at
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+// locked - выполнение fn под advisory lock со списком уже применённых версий
+func (m *Migrator) locked(ctx context.Context, fn func(applied map[int]time.Time) error) (err error) {
+	if _, err := m.conn.Exec(ctx, lockQuery, lockKey); err != nil {
+		return errors.Wrap(err, "failed to acquire migration lock")
+	}
+	defer func() {
+		// Контекст мог быть отменён, но блокировку нужно снять в любом случае
+		if _, uErr := m.conn.Exec(context.Background(), unlockQuery, lockKey); uErr != nil && err == nil {
+			err = errors.Wrap(uErr, "failed to release migration lock")
+		}
+	}()
+
+	if _, err := m.conn.Exec(ctx, createTableQuery); err != nil {
+		return errors.Wrap(err, "failed to create schema_migrations")
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	return fn(applied)
+}
+
+// applied - версии применённых миграций и время применения
+func (m *Migrator) applied(ctx context.Context) (map[int]time.Time, error) {
+	rows, err := m.conn.Query(ctx, appliedQuery)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read schema_migrations")
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var (
+			version int
+			at      time.Time
+		)
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, errors.Wrap(err, "failed to scan schema_migrations")
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// apply - выполнение SQL миграции и запись об этом в одной транзакции
+func (m *Migrator) apply(ctx context.Context, sql, record string, args ...any) error {
+	return pgx.BeginFunc(ctx, m.conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, record, args...)
+		return err
+	})
+}
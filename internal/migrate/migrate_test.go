@@ -0,0 +1,34 @@
+package migrate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseline(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "task"},
+		{Version: 2, Name: "task_updated_at"},
+		{Version: 3, Name: "users"},
+	}
+
+	t.Run("отмечаются миграции до версии включительно", func(t *testing.T) {
+		got, err := baseline(migrations, map[int]time.Time{}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, migrations[:2], got)
+	})
+
+	t.Run("применённые миграции пропускаются", func(t *testing.T) {
+		got, err := baseline(migrations, map[int]time.Time{1: time.Now()}, 3)
+		require.NoError(t, err)
+		assert.Equal(t, migrations[1:], got)
+	})
+
+	t.Run("неизвестная версия", func(t *testing.T) {
+		_, err := baseline(migrations, map[int]time.Time{}, 4)
+		assert.Error(t, err)
+	})
+}
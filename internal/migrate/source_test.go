@@ -0,0 +1,58 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"simple-service/migrations"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("сортировка по версии", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"000002_second.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+			"000001_first.up.sql":    {Data: []byte("CREATE TABLE a ();")},
+			"000001_first.down.sql":  {Data: []byte("DROP TABLE a;")},
+			"README.md":              {Data: []byte("not a migration")},
+			"000003_no_down.up.sql":  {Data: []byte("SELECT 1;")},
+			"000002_second.down.sql": {Data: []byte("DROP TABLE b;")},
+		}
+
+		got, err := Load(fsys)
+		require.NoError(t, err)
+		assert.Equal(t, []Migration{
+			{Version: 1, Name: "first", Up: "CREATE TABLE a ();", Down: "DROP TABLE a;"},
+			{Version: 2, Name: "second", Up: "CREATE TABLE b ();", Down: "DROP TABLE b;"},
+			{Version: 3, Name: "no_down", Up: "SELECT 1;"},
+		}, got)
+	})
+
+	t.Run("нет файла up", func(t *testing.T) {
+		_, err := Load(fstest.MapFS{"000001_first.down.sql": {Data: []byte("DROP TABLE a;")}})
+		assert.Error(t, err)
+	})
+
+	t.Run("разные имена у одной версии", func(t *testing.T) {
+		_, err := Load(fstest.MapFS{
+			"000001_first.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+			"000001_other.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+			"000001_first.down.sql": {Data: []byte("DROP TABLE a;")},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := Load(migrations.Postgres())
+	require.NoError(t, err)
+	require.NotEmpty(t, got)
+
+	// Версии идут подряд, у каждой миграции есть откат
+	for i, m := range got {
+		assert.Equal(t, i+1, m.Version)
+		assert.NotEmpty(t, m.Down, m.Name)
+	}
+}
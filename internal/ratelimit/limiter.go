@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Ограничение частоты запросов алгоритмом token bucket, отдельная корзина на каждый ключ
+
+// Limiter - набор корзин токенов. Корзина пополняется со скоростью rate токенов в секунду
+// и вмещает не больше burst токенов, каждый запрос забирает один токен
+type Limiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter - конструктор ограничителя
+func NewLimiter(rate float64, burst int) *Limiter {
+	return &Limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow - забирает токен из корзины key. Если токенов нет, возвращает false
+// и время, через которое появится следующий токен
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	if l.rate <= 0 {
+		return false, time.Duration(math.MaxInt64)
+	}
+	wait := (1 - b.tokens) / l.rate
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// sweep - удаление корзин, которые за время простоя успели заполниться:
+// новая корзина для того же ключа будет такой же, поэтому память не растёт с числом клиентов
+func (l *Limiter) sweep(now time.Time) {
+	if l.rate <= 0 {
+		return
+	}
+	fill := time.Duration(l.burst / l.rate * float64(time.Second))
+	if now.Sub(l.lastSweep) < fill {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= fill {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newLimiter := func(rate float64, burst int) (*Limiter, *time.Time) {
+		now := start
+		l := NewLimiter(rate, burst)
+		l.now = func() time.Time { return now }
+		return l, &now
+	}
+
+	t.Run("Всплеск до burst, затем отказ", func(t *testing.T) {
+		l, _ := newLimiter(1, 3)
+
+		for i := 0; i < 3; i++ {
+			ok, _ := l.Allow("alice")
+			assert.True(t, ok)
+		}
+
+		ok, wait := l.Allow("alice")
+		assert.False(t, ok)
+		assert.Equal(t, time.Second, wait)
+	})
+
+	t.Run("Корзина пополняется со временем", func(t *testing.T) {
+		l, now := newLimiter(2, 1)
+
+		ok, _ := l.Allow("alice")
+		assert.True(t, ok)
+		ok, wait := l.Allow("alice")
+		assert.False(t, ok)
+		assert.Equal(t, 500*time.Millisecond, wait)
+
+		*now = now.Add(500 * time.Millisecond)
+		ok, _ = l.Allow("alice")
+		assert.True(t, ok)
+	})
+
+	t.Run("Корзины разных ключей независимы", func(t *testing.T) {
+		l, _ := newLimiter(1, 1)
+
+		ok, _ := l.Allow("alice")
+		assert.True(t, ok)
+		ok, _ = l.Allow("bob")
+		assert.True(t, ok)
+		ok, _ = l.Allow("alice")
+		assert.False(t, ok)
+	})
+
+	t.Run("Заполнившиеся корзины удаляются", func(t *testing.T) {
+		l, now := newLimiter(1, 2)
+
+		l.Allow("alice")
+		l.Allow("bob")
+		assert.Len(t, l.buckets, 2)
+
+		*now = now.Add(2 * time.Second)
+		l.Allow("carol")
+		assert.Len(t, l.buckets, 1)
+		assert.Contains(t, l.buckets, "carol")
+	})
+}
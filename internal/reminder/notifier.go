@@ -0,0 +1,38 @@
+package reminder
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"simple-service/internal/repo"
+)
+
+// Notifier - получатель событий о наступивших напоминаниях.
+// Ошибка означает, что напоминание не доставлено, и планировщик вернёт его в очередь.
+// Notify должен прерываться по отмене ctx: после неё аренда напоминания может закончиться.
+// Повторная доставка возможна, поэтому получатель должен быть идемпотентным по TaskID и RemindAt
+type Notifier interface {
+	Notify(ctx context.Context, reminder repo.Reminder) error
+}
+
+type logNotifier struct {
+	log *zap.SugaredLogger
+}
+
+// NewLogNotifier - получатель, который записывает напоминания в лог
+func NewLogNotifier(log *zap.SugaredLogger) Notifier {
+	return &logNotifier{log: log}
+}
+
+// Notify - запись напоминания в лог
+func (n *logNotifier) Notify(_ context.Context, reminder repo.Reminder) error {
+	n.log.Infow("Task reminder",
+		"task_id", reminder.TaskID,
+		"owner", reminder.Owner,
+		"title", reminder.Title,
+		"due_at", reminder.DueAt,
+		"remind_at", reminder.RemindAt,
+	)
+	return nil
+}
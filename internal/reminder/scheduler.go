@@ -0,0 +1,147 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.uber.org/zap"
+
+	"simple-service/internal/config"
+	"simple-service/internal/metrics"
+	"simple-service/internal/repo"
+)
+
+// Фоновая отправка напоминаний. Реплики опрашивают общую БД, и одновременно напоминание
+// забирает только одна из них, поэтому планировщик можно запускать во всех репликах.
+// Пачка отправляется последовательно, поэтому перед каждой отправкой аренда продлевается,
+// а получатель ограничен половиной аренды: запас покрывает расхождение часов и запись отметки.
+// Напоминание отмечается отправленным после доставки, так что доставка выходит «хотя бы один раз»:
+// если реплика упала между доставкой и отметкой, после окончания аренды его отправит другая
+
+// Store - хранилище, из которого планировщик забирает напоминания
+type Store interface {
+	ClaimReminders(ctx context.Context, limit int, lease time.Duration) ([]repo.Reminder, error)
+	RenewReminder(ctx context.Context, reminder repo.Reminder) (repo.Reminder, error)
+	CompleteReminder(ctx context.Context, reminder repo.Reminder) error
+	ReleaseReminder(ctx context.Context, reminder repo.Reminder) error
+}
+
+// Scheduler - периодическая отправка наступивших напоминаний
+type Scheduler struct {
+	store    Store
+	notifier Notifier
+	interval time.Duration
+	lease    time.Duration
+	batch    int
+	log      *zap.SugaredLogger
+}
+
+// NewScheduler - конструктор планировщика напоминаний
+func NewScheduler(store Store, notifier Notifier, cfg config.Reminders, log *zap.SugaredLogger) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		notifier: notifier,
+		interval: cfg.Interval,
+		lease:    cfg.Lease,
+		batch:    cfg.BatchSize,
+		log:      log,
+	}
+}
+
+// Run - отправка напоминаний раз в interval до отмены ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Tick(ctx)
+		}
+	}
+}
+
+// Tick - один проход: забирает пачки напоминаний, пока они не закончатся, и возвращает число отправленных
+func (s *Scheduler) Tick(ctx context.Context) int {
+	sent := 0
+	for ctx.Err() == nil {
+		reminders, err := s.store.ClaimReminders(ctx, s.batch, s.lease)
+		if err != nil {
+			s.log.Errorw("Failed to claim reminders", "error", err)
+			return sent
+		}
+
+		failed := 0
+		for i, reminder := range reminders {
+			if ctx.Err() != nil {
+				// Остаток пачки возвращается сразу, а не после окончания аренды
+				s.release(ctx, reminders[i:])
+				return sent
+			}
+			switch err := s.notify(ctx, reminder); {
+			case err == nil:
+				sent++
+			case errors.Is(err, repo.ErrReminderLost):
+				// Напоминание отправит другая реплика, либо оно уже не нужно
+			default:
+				failed++
+			}
+		}
+
+		// Неполная пачка - напоминаний больше нет. При ошибках отправки повторим на следующем тике,
+		// иначе возвращённые напоминания тут же были бы забраны снова
+		if len(reminders) < s.batch || failed > 0 {
+			return sent
+		}
+	}
+	return sent
+}
+
+// notify - отправка одного напоминания. После доставки оно отмечается отправленным,
+// при ошибке аренда снимается, чтобы напоминание отправили на следующем проходе
+func (s *Scheduler) notify(ctx context.Context, reminder repo.Reminder) error {
+	// Отметки пишутся и при остановке сервиса, иначе напоминание ждало бы конца аренды
+	storeCtx := context.WithoutCancel(ctx)
+
+	renewed, err := s.store.RenewReminder(ctx, reminder)
+	if errors.Is(err, repo.ErrReminderLost) {
+		s.log.Warnw("Reminder lease lost before sending", "task_id", reminder.TaskID)
+		return err
+	}
+	if err != nil {
+		s.log.Errorw("Failed to renew reminder", "task_id", reminder.TaskID, "error", err)
+		s.release(ctx, []repo.Reminder{reminder})
+		return err
+	}
+	reminder = renewed
+
+	notifyCtx, cancel := context.WithTimeout(ctx, s.lease/2)
+	err = s.notifier.Notify(notifyCtx, reminder)
+	cancel()
+	if err != nil {
+		s.log.Errorw("Failed to send reminder", "task_id", reminder.TaskID, "error", err)
+		s.release(ctx, []repo.Reminder{reminder})
+		return err
+	}
+
+	metrics.RemindersSent.Inc()
+	if err := s.store.CompleteReminder(storeCtx, reminder); err != nil {
+		// Напоминание доставлено, но его могут отправить ещё раз
+		s.log.Errorw("Failed to mark reminder as sent", "task_id", reminder.TaskID, "error", err)
+	}
+	return nil
+}
+
+// release - досрочное окончание аренды напоминаний
+func (s *Scheduler) release(ctx context.Context, reminders []repo.Reminder) {
+	storeCtx := context.WithoutCancel(ctx)
+	for _, reminder := range reminders {
+		if err := s.store.ReleaseReminder(storeCtx, reminder); err != nil {
+			s.log.Errorw("Failed to release reminder", "task_id", reminder.TaskID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"simple-service/internal/config"
+	"simple-service/internal/repo"
+	"simple-service/internal/repo/mocks"
+)
+
+// failingNotifier - получатель, который не принимает напоминания по выбранным задачам
+type failingNotifier struct {
+	failed    map[int]bool
+	sent      []int
+	deadlines []time.Duration
+}
+
+func (n *failingNotifier) Notify(ctx context.Context, reminder repo.Reminder) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		n.deadlines = append(n.deadlines, time.Until(deadline))
+	}
+	if n.failed[reminder.TaskID] {
+		return errors.New("notifier unavailable")
+	}
+	n.sent = append(n.sent, reminder.TaskID)
+	return nil
+}
+
+const lease = 5 * time.Minute
+
+// newTestScheduler - планировщик с пачкой из двух напоминаний
+func newTestScheduler(store Store, notifier Notifier) *Scheduler {
+	cfg := config.Reminders{Interval: time.Minute, Lease: lease, BatchSize: 2}
+	return NewScheduler(store, notifier, cfg, zap.NewNop().Sugar())
+}
+
+// TestSchedulerTick - тестирование одного прохода планировщика
+func TestSchedulerTick(t *testing.T) {
+	ctx := context.Background()
+	due := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	claimed := due.Add(-30 * time.Minute)
+	reminder := func(id int) repo.Reminder {
+		return repo.Reminder{TaskID: id, Owner: "user", Title: "Task", DueAt: due, RemindAt: due.Add(-time.Hour), ClaimedAt: claimed}
+	}
+	// renewed - напоминание после продления аренды
+	renewed := func(id int) repo.Reminder {
+		rem := reminder(id)
+		rem.ClaimedAt = claimed.Add(time.Minute)
+		return rem
+	}
+	expectRenew := func(mockRepo *mocks.Repository, id int) {
+		mockRepo.On("RenewReminder", mock.Anything, reminder(id)).Return(renewed(id), nil).Once()
+	}
+
+	t.Run("напоминания забираются пачками до конца", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		mockRepo.On("ClaimReminders", mock.Anything, 2, lease).Return([]repo.Reminder{reminder(1), reminder(2)}, nil).Once()
+		mockRepo.On("ClaimReminders", mock.Anything, 2, lease).Return([]repo.Reminder{reminder(3)}, nil).Once()
+		for id := 1; id <= 3; id++ {
+			expectRenew(mockRepo, id)
+			mockRepo.On("CompleteReminder", mock.Anything, renewed(id)).Return(nil).Once()
+		}
+		notifier := &failingNotifier{}
+
+		sent := newTestScheduler(mockRepo, notifier).Tick(ctx)
+		assert.Equal(t, 3, sent)
+		assert.Equal(t, []int{1, 2, 3}, notifier.sent)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("получатель укладывается в аренду", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		mockRepo.On("ClaimReminders", mock.Anything, 2, lease).Return([]repo.Reminder{reminder(1)}, nil).Once()
+		expectRenew(mockRepo, 1)
+		mockRepo.On("CompleteReminder", mock.Anything, renewed(1)).Return(nil).Once()
+		notifier := &failingNotifier{}
+
+		newTestScheduler(mockRepo, notifier).Tick(ctx)
+		if assert.Len(t, notifier.deadlines, 1) {
+			assert.LessOrEqual(t, notifier.deadlines[0], lease/2)
+		}
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("напоминание с потерянной арендой пропускается", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		mockRepo.On("ClaimReminders", mock.Anything, 2, lease).Return([]repo.Reminder{reminder(1), reminder(2)}, nil).Once()
+		mockRepo.On("RenewReminder", mock.Anything, reminder(1)).Return(repo.Reminder{}, repo.ErrReminderLost).Once()
+		expectRenew(mockRepo, 2)
+		mockRepo.On("CompleteReminder", mock.Anything, renewed(2)).Return(nil).Once()
+		mockRepo.On("ClaimReminders", mock.Anything, 2, lease).Return(nil, nil).Once()
+		notifier := &failingNotifier{}
+
+		assert.Equal(t, 1, newTestScheduler(mockRepo, notifier).Tick(ctx))
+		assert.Equal(t, []int{2}, notifier.sent)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("неотправленное напоминание возвращается", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		mockRepo.On("ClaimReminders", mock.Anything, 2, lease).Return([]repo.Reminder{reminder(1), reminder(2)}, nil).Once()
+		expectRenew(mockRepo, 1)
+		expectRenew(mockRepo, 2)
+		mockRepo.On("CompleteReminder", mock.Anything, renewed(1)).Return(nil).Once()
+		mockRepo.On("ReleaseReminder", mock.Anything, renewed(2)).Return(nil).Once()
+		notifier := &failingNotifier{failed: map[int]bool{2: true}}
+
+		sent := newTestScheduler(mockRepo, notifier).Tick(ctx)
+		assert.Equal(t, 1, sent)
+		assert.Equal(t, []int{1}, notifier.sent)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ошибка отметки не отменяет доставку", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		mockRepo.On("ClaimReminders", mock.Anything, 2, lease).Return([]repo.Reminder{reminder(1)}, nil).Once()
+		expectRenew(mockRepo, 1)
+		mockRepo.On("CompleteReminder", mock.Anything, renewed(1)).Return(repo.ErrReminderLost).Once()
+		notifier := &failingNotifier{}
+
+		assert.Equal(t, 1, newTestScheduler(mockRepo, notifier).Tick(ctx))
+		assert.Equal(t, []int{1}, notifier.sent)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("при остановке остаток пачки возвращается", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		mockRepo := new(mocks.Repository)
+		mockRepo.On("ClaimReminders", mock.Anything, 2, lease).Return([]repo.Reminder{reminder(1), reminder(2)}, nil).Once()
+		expectRenew(mockRepo, 1)
+		mockRepo.On("CompleteReminder", mock.Anything, renewed(1)).Run(func(mock.Arguments) { cancel() }).Return(nil).Once()
+		mockRepo.On("ReleaseReminder", mock.Anything, reminder(2)).Return(nil).Once()
+		notifier := &failingNotifier{}
+
+		assert.Equal(t, 1, newTestScheduler(mockRepo, notifier).Tick(ctx))
+		assert.Equal(t, []int{1}, notifier.sent)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ошибка БД", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		mockRepo.On("ClaimReminders", mock.Anything, 2, lease).Return(nil, errors.New("DB error")).Once()
+
+		assert.Zero(t, newTestScheduler(mockRepo, &failingNotifier{}).Tick(ctx))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// TestLogNotifier - напоминание записывается в лог
+func TestLogNotifier(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	notifier := NewLogNotifier(zap.New(core).Sugar())
+
+	err := notifier.Notify(context.Background(), repo.Reminder{TaskID: 7, Owner: "user", Title: "Task"})
+	assert.NoError(t, err)
+
+	entries := logs.FilterMessage("Task reminder").All()
+	if assert.Len(t, entries, 1) {
+		assert.EqualValues(t, 7, entries[0].ContextMap()["task_id"])
+		assert.Equal(t, "user", entries[0].ContextMap()["owner"])
+	}
+}
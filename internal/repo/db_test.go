@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/stretchr/testify/require"
+
+	"simple-service/internal/config"
+	"simple-service/internal/migrate"
+	"simple-service/migrations"
+)
+
+// testRepository - репозиторий на тестовой БД, которая задаётся переменными TEST_DB_HOST, TEST_DB_PORT и т.д.
+// Без TEST_DB_HOST тест пропускается. Схема приводится к последней миграции, таблицы очищаются
+func testRepository(t *testing.T) *repository {
+	t.Helper()
+	if os.Getenv("TEST_DB_HOST") == "" {
+		t.Skip("TEST_DB_HOST is not set")
+	}
+
+	var cfg config.PostgreSQL
+	require.NoError(t, envconfig.Process("TEST", &cfg))
+	ctx := context.Background()
+
+	migrator, err := migrate.Connect(ctx, cfg, migrations.Postgres())
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, migrator.Close(ctx))
+	require.NoError(t, err)
+
+	r, err := NewRepository(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+
+	rep := r.(*repository)
+	_, err = rep.pool.Exec(ctx, `TRUNCATE tasks, idempotency_keys, users RESTART IDENTITY CASCADE`)
+	require.NoError(t, err)
+	return rep
+}
+
+// createTestTask - создание задачи владельца owner
+func createTestTask(t *testing.T, r *repository, owner string, task Task) int {
+	t.Helper()
+	id, err := r.CreateTask(context.Background(), owner, task)
+	require.NoError(t, err)
+	return id
+}
@@ -1,7 +1,132 @@
 package repo
 
+import (
+	"encoding/json"
+	"time"
+)
+
+// TaskStatus - статус задачи, допустимые значения ограничены CHECK в таблице tasks
+type TaskStatus string
+
+const (
+	StatusNew        TaskStatus = "new"
+	StatusInProgress TaskStatus = "in_progress"
+	StatusDone       TaskStatus = "done"
+)
+
 // Task - структура, соответствующая таблице tasks
 type Task struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      TaskStatus `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Version     int        `json:"version"`   // Увеличивается при каждом изменении задачи
+	DueAt       *time.Time `json:"due_at"`    // Срок выполнения
+	RemindAt    *time.Time `json:"remind_at"` // Время напоминания, не позже DueAt
+}
+
+// TaskPatch - частичное обновление задачи, nil-поля и незаданные NullableTime остаются без изменений
+type TaskPatch struct {
+	Title       *string
+	Description *string
+	DueAt       NullableTime
+	RemindAt    NullableTime
+}
+
+// NullableTime - новое значение необязательного времени при частичном обновлении.
+// Set=false - поле не передано и не меняется, Set=true с Time=nil - поле очищается
+type NullableTime struct {
+	Time *time.Time
+	Set  bool
+}
+
+// UnmarshalJSON - поле есть в теле запроса; null очищает значение
+func (t *NullableTime) UnmarshalJSON(data []byte) error {
+	t.Set = true
+	if string(data) == "null" {
+		t.Time = nil
+		return nil
+	}
+	var value time.Time
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	t.Time = &value
+	return nil
+}
+
+// TaskSort - поле сортировки списка задач
+type TaskSort string
+
+const (
+	SortCreatedAt TaskSort = "created_at"
+	SortUpdatedAt TaskSort = "updated_at"
+	SortTitle     TaskSort = "title"
+)
+
+// TaskCursor - позиция последней задачи страницы для keyset-пагинации.
+// Заполняется значение поля сортировки и id задачи
+type TaskCursor struct {
+	ID    int       `json:"id"`
+	Time  time.Time `json:"time,omitempty"`
+	Title string    `json:"title,omitempty"`
+}
+
+// TaskFilter - параметры выборки списка задач
+type TaskFilter struct {
+	Status      *TaskStatus
+	CreatedFrom *time.Time // Включительно
+	CreatedTo   *time.Time // Не включительно
+	Overdue     bool       // Только незавершённые задачи с истёкшим сроком
+	Sort        TaskSort
+	Desc        bool
+	After       *TaskCursor // Задачи после этой позиции в порядке сортировки
+	Limit       int
+}
+
+// TaskPage - страница списка задач, Next равен nil на последней странице
+type TaskPage struct {
+	Tasks []Task
+	Next  *TaskCursor
+}
+
+// SearchLanguage - язык полнотекстового поиска, определяет правила стемминга
+type SearchLanguage string
+
+const (
+	LanguageRussian SearchLanguage = "ru"
+	LanguageEnglish SearchLanguage = "en"
+)
+
+// TaskSearchResult - найденная задача с релевантностью и подсвеченными фрагментами
+type TaskSearchResult struct {
+	Task
+	Rank      float32       `json:"rank"`
+	Highlight TaskHighlight `json:"highlight"`
+}
+
+// TaskHighlight - фрагменты заголовка и описания в виде HTML: текст задачи экранирован,
+// совпадения обёрнуты в <b></b>, поэтому фрагмент можно вставлять в страницу как есть
+type TaskHighlight struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 }
+
+// IdempotencyRecord - запрос с ключом идемпотентности и сохранённый ответ на него
+type IdempotencyRecord struct {
+	RequestHash string
+	StatusCode  int // 0, пока первый запрос с этим ключом ещё выполняется
+	Response    []byte
+}
+
+// Reminder - напоминание о задаче, время которого наступило
+type Reminder struct {
+	TaskID    int
+	Owner     string
+	Title     string
+	DueAt     time.Time
+	RemindAt  time.Time
+	ClaimedAt time.Time // Начало аренды; продление и отметки проходят, только пока аренду не забрали
+}
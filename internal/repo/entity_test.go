@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullableTime(t *testing.T) {
+	due := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		body string
+		want NullableTime
+	}{
+		{name: "Поля нет - без изменений", body: `{}`, want: NullableTime{}},
+		{name: "null очищает поле", body: `{"due_at": null}`, want: NullableTime{Set: true}},
+		{name: "Новое значение", body: `{"due_at": "2026-01-02T15:00:00Z"}`, want: NullableTime{Time: &due, Set: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch struct {
+				DueAt NullableTime `json:"due_at"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &patch))
+			assert.Equal(t, tt.want, patch.DueAt)
+		})
+	}
+
+	t.Run("Некорректное время", func(t *testing.T) {
+		var patch struct {
+			DueAt NullableTime `json:"due_at"`
+		}
+		assert.Error(t, json.Unmarshal([]byte(`{"due_at": "tomorrow"}`), &patch))
+	})
+}
+
+func TestPatchTaskQuery(t *testing.T) {
+	// Срок и напоминание меняются по флагу, а не по NULL, иначе их нельзя было бы очистить
+	assert.Contains(t, patchTaskQuery, "due_at=CASE WHEN $8::bool THEN $6::timestamptz ELSE due_at END")
+	assert.Contains(t, patchTaskQuery, "remind_at=CASE WHEN $9::bool THEN $7::timestamptz ELSE remind_at END")
+	assert.NotContains(t, patchTaskQuery, "COALESCE($6")
+}
@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+// Ключи идемпотентности. Ключ резервируется до выполнения запроса, поэтому параллельный
+// повтор с тем же ключом видит незавершённую запись, а не создаёт дубль
+
+const (
+	// Просроченный ключ перезаписывается, действующий остаётся без изменений и запрос ничего не возвращает.
+	// Резерв без ответа, которому больше $5 секунд, тоже перезаписывается: запрос, который его занял,
+	// завершился аварийно или не смог сохранить ответ
+	reserveIdempotencyKeyQuery = `WITH ` + ownerCTE + `
+		INSERT INTO idempotency_keys (owner_id, key, request_hash, expires_at)
+		SELECT id, $2, $3, now() + make_interval(secs => $4) FROM owner
+		ON CONFLICT (owner_id, key) DO UPDATE
+			SET request_hash=EXCLUDED.request_hash, status_code=NULL, response=NULL,
+				created_at=now(), expires_at=EXCLUDED.expires_at
+			WHERE idempotency_keys.expires_at <= now()
+				OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at <= now() - make_interval(secs => $5))
+		RETURNING key`
+	getIdempotencyKeyQuery = `SELECT request_hash, COALESCE(status_code, 0), response FROM idempotency_keys
+		WHERE owner_id=` + ownerIDQuery + ` AND key=($2) AND expires_at > now()`
+	saveIdempotentResponseQuery = `UPDATE idempotency_keys SET status_code=($3), response=($4)
+		WHERE owner_id=` + ownerIDQuery + ` AND key=($2)`
+	deleteIdempotencyKeyQuery = `DELETE FROM idempotency_keys WHERE owner_id=` + ownerIDQuery + ` AND key=($2)`
+	purgeIdempotencyKeysQuery = `DELETE FROM idempotency_keys WHERE expires_at <= now()`
+)
+
+// ReserveIdempotencyKey - резервирование ключа для нового запроса на время ttl.
+// Резерв без сохранённого ответа можно занять заново через lockTimeout.
+// Если действующий ключ уже есть, возвращается его запись, иначе nil
+func (r *repository) ReserveIdempotencyKey(ctx context.Context, owner, key, requestHash string, ttl, lockTimeout time.Duration) (*IdempotencyRecord, error) {
+	// Вторая попытка нужна, если ключ истёк между резервированием и чтением
+	// или того же нового пользователя параллельно создал другой запрос
+	for attempt := 0; attempt < 2; attempt++ {
+		var reserved string
+		err := r.pool.QueryRow(ctx, reserveIdempotencyKeyQuery, owner, key, requestHash, ttl.Seconds(), lockTimeout.Seconds()).Scan(&reserved)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.Wrap(err, "failed to reserve idempotency key")
+		}
+
+		var record IdempotencyRecord
+		err = r.pool.QueryRow(ctx, getIdempotencyKeyQuery, owner, key).Scan(&record.RequestHash, &record.StatusCode, &record.Response)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get idempotency key")
+		}
+		return &record, nil
+	}
+	return nil, errors.New("failed to reserve idempotency key: key expired concurrently")
+}
+
+// SaveIdempotentResponse - сохранение ответа для зарезервированного ключа
+func (r *repository) SaveIdempotentResponse(ctx context.Context, owner, key string, statusCode int, response []byte) error {
+	if _, err := r.pool.Exec(ctx, saveIdempotentResponseQuery, owner, key, statusCode, response); err != nil {
+		return errors.Wrap(err, "failed to save idempotent response")
+	}
+	return nil
+}
+
+// DeleteIdempotencyKey - снятие резерва, если запрос не удалось выполнить
+func (r *repository) DeleteIdempotencyKey(ctx context.Context, owner, key string) error {
+	if _, err := r.pool.Exec(ctx, deleteIdempotencyKeyQuery, owner, key); err != nil {
+		return errors.Wrap(err, "failed to delete idempotency key")
+	}
+	return nil
+}
+
+// PurgeIdempotencyKeys - удаление просроченных ключей, возвращает количество удалённых
+func (r *repository) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	tag, err := r.pool.Exec(ctx, purgeIdempotencyKeysQuery)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to purge idempotency keys")
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,89 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserveIdempotencyKey(t *testing.T) {
+	r := testRepository(t)
+	ctx := context.Background()
+	const (
+		ttl         = 24 * time.Hour
+		lockTimeout = time.Minute
+	)
+
+	reserve := func(t *testing.T, owner, key, hash string) *IdempotencyRecord {
+		t.Helper()
+		record, err := r.ReserveIdempotencyKey(ctx, owner, key, hash, ttl, lockTimeout)
+		require.NoError(t, err)
+		return record
+	}
+	// shift - сдвиг времени резерва и срока действия ключа в прошлое
+	shift := func(t *testing.T, key, column string, by time.Duration) {
+		t.Helper()
+		_, err := r.pool.Exec(ctx, `UPDATE idempotency_keys SET `+column+`=`+column+` - make_interval(secs => $2) WHERE key=$1`,
+			key, by.Seconds())
+		require.NoError(t, err)
+	}
+
+	t.Run("повтор видит незавершённый резерв", func(t *testing.T) {
+		assert.Nil(t, reserve(t, "alice", "pending", "hash"))
+		assert.Equal(t, &IdempotencyRecord{RequestHash: "hash"}, reserve(t, "alice", "pending", "other"))
+	})
+
+	t.Run("повтор получает сохранённый ответ", func(t *testing.T) {
+		assert.Nil(t, reserve(t, "alice", "done", "hash"))
+		require.NoError(t, r.SaveIdempotentResponse(ctx, "alice", "done", 201, []byte(`{"id":1}`)))
+
+		assert.Equal(t, &IdempotencyRecord{RequestHash: "hash", StatusCode: 201, Response: []byte(`{"id":1}`)},
+			reserve(t, "alice", "done", "hash"))
+	})
+
+	t.Run("ключи разных пользователей не пересекаются", func(t *testing.T) {
+		assert.Nil(t, reserve(t, "alice", "shared", "hash"))
+		assert.Nil(t, reserve(t, "bob", "shared", "hash"))
+	})
+
+	t.Run("зависший резерв занимается заново после lockTimeout", func(t *testing.T) {
+		assert.Nil(t, reserve(t, "alice", "stale", "hash"))
+		shift(t, "stale", "created_at", 30*time.Second)
+		assert.NotNil(t, reserve(t, "alice", "stale", "hash"), "резерв моложе lockTimeout")
+
+		shift(t, "stale", "created_at", time.Minute)
+		assert.Nil(t, reserve(t, "alice", "stale", "new-hash"))
+		// Новый резерв свежий и принадлежит новому запросу
+		assert.Equal(t, &IdempotencyRecord{RequestHash: "new-hash"}, reserve(t, "alice", "stale", "hash"))
+	})
+
+	t.Run("сохранённый ответ не занимается по lockTimeout", func(t *testing.T) {
+		assert.Nil(t, reserve(t, "alice", "old-done", "hash"))
+		require.NoError(t, r.SaveIdempotentResponse(ctx, "alice", "old-done", 201, []byte(`{}`)))
+		shift(t, "old-done", "created_at", time.Hour)
+
+		record := reserve(t, "alice", "old-done", "hash")
+		require.NotNil(t, record)
+		assert.Equal(t, 201, record.StatusCode)
+	})
+
+	t.Run("просроченный ключ занимается заново", func(t *testing.T) {
+		assert.Nil(t, reserve(t, "alice", "expired", "hash"))
+		require.NoError(t, r.SaveIdempotentResponse(ctx, "alice", "expired", 201, []byte(`{}`)))
+		shift(t, "expired", "expires_at", ttl+time.Second)
+
+		purged, err := r.PurgeIdempotencyKeys(ctx)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, purged)
+		assert.Nil(t, reserve(t, "alice", "expired", "new-hash"))
+	})
+
+	t.Run("снятый резерв занимается заново", func(t *testing.T) {
+		assert.Nil(t, reserve(t, "alice", "deleted", "hash"))
+		require.NoError(t, r.DeleteIdempotencyKey(ctx, "alice", "deleted"))
+		assert.Nil(t, reserve(t, "alice", "deleted", "hash"))
+	})
+}
@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Построение запроса списка задач с фильтрами и keyset-пагинацией
+
+// sortColumns - допустимые поля сортировки, в запрос попадают только они
+var sortColumns = map[TaskSort]string{
+	SortCreatedAt: "created_at",
+	SortUpdatedAt: "updated_at",
+	SortTitle:     "title",
+}
+
+// buildListQuery - SQL-запрос и его параметры для выборки страницы задач владельца.
+// Запрашивается на одну задачу больше лимита, чтобы понять, есть ли следующая страница
+func buildListQuery(owner string, f TaskFilter) (string, []any, error) {
+	column, ok := sortColumns[f.Sort]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown sort field %q", f.Sort)
+	}
+	if f.Limit <= 0 {
+		return "", nil, fmt.Errorf("limit must be positive, got %d", f.Limit)
+	}
+
+	args := []any{owner}
+	conds := []string{"owner_id=" + ownerIDQuery}
+	where := func(cond string, value any) {
+		args = append(args, value)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.Status != nil {
+		where("status=($%d)", *f.Status)
+	}
+	if f.CreatedFrom != nil {
+		where("created_at>=($%d)", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		where("created_at<($%d)", *f.CreatedTo)
+	}
+	if f.Overdue {
+		conds = append(conds, "due_at<now()", "status<>'done'")
+	}
+
+	dir, cmp := "ASC", ">"
+	if f.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	if f.After != nil {
+		args = append(args, cursorValue(f.Sort, *f.After), f.After.ID)
+		conds = append(conds, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, cmp, len(args)-1, len(args)))
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM tasks WHERE %s ORDER BY %s %s, id %s LIMIT %d`,
+		taskColumns, strings.Join(conds, " AND "), column, dir, dir, f.Limit+1)
+
+	return query, args, nil
+}
+
+// cursorValue - значение поля сортировки, сохранённое в курсоре
+func cursorValue(sort TaskSort, c TaskCursor) any {
+	if sort == SortTitle {
+		return c.Title
+	}
+	return c.Time
+}
+
+// cursorOf - курсор, указывающий на задачу task
+func cursorOf(sort TaskSort, task Task) *TaskCursor {
+	c := &TaskCursor{ID: task.ID}
+	switch sort {
+	case SortTitle:
+		c.Title = task.Title
+	case SortUpdatedAt:
+		c.Time = task.UpdatedAt
+	default:
+		c.Time = task.CreatedAt
+	}
+	return c
+}
@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildListQuery(t *testing.T) {
+	status := StatusDone
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	after := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    TaskFilter
+		wantQuery string
+		wantArgs  []any
+		wantErr   bool
+	}{
+		{
+			name:      "первая страница",
+			filter:    TaskFilter{Sort: SortCreatedAt, Limit: 20},
+			wantQuery: `SELECT ` + taskColumns + ` FROM tasks WHERE owner_id=` + ownerIDQuery + ` ORDER BY created_at ASC, id ASC LIMIT 21`,
+			wantArgs:  []any{"user"},
+		},
+		{
+			name: "фильтры и курсор",
+			filter: TaskFilter{
+				Status:      &status,
+				CreatedFrom: &from,
+				CreatedTo:   &to,
+				Sort:        SortUpdatedAt,
+				Desc:        true,
+				After:       &TaskCursor{ID: 7, Time: after},
+				Limit:       10,
+			},
+			wantQuery: `SELECT ` + taskColumns + ` FROM tasks WHERE owner_id=` + ownerIDQuery +
+				` AND status=($2) AND created_at>=($3) AND created_at<($4) AND (updated_at, id) < ($5, $6)` +
+				` ORDER BY updated_at DESC, id DESC LIMIT 11`,
+			wantArgs: []any{"user", status, from, to, after, 7},
+		},
+		{
+			name:      "просроченные задачи",
+			filter:    TaskFilter{Overdue: true, Sort: SortCreatedAt, Limit: 20},
+			wantQuery: `SELECT ` + taskColumns + ` FROM tasks WHERE owner_id=` + ownerIDQuery + ` AND due_at<now() AND status<>'done' ORDER BY created_at ASC, id ASC LIMIT 21`,
+			wantArgs:  []any{"user"},
+		},
+		{
+			name:      "сортировка по заголовку",
+			filter:    TaskFilter{Sort: SortTitle, After: &TaskCursor{ID: 3, Title: "b"}, Limit: 5},
+			wantQuery: `SELECT ` + taskColumns + ` FROM tasks WHERE owner_id=` + ownerIDQuery + ` AND (title, id) > ($2, $3) ORDER BY title ASC, id ASC LIMIT 6`,
+			wantArgs:  []any{"user", "b", 3},
+		},
+		{
+			name:    "неизвестное поле сортировки",
+			filter:  TaskFilter{Sort: "id; DROP TABLE tasks", Limit: 5},
+			wantErr: true,
+		},
+		{
+			name:    "нулевой лимит",
+			filter:  TaskFilter{Sort: SortTitle},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := buildListQuery("user", tt.filter)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantQuery, query)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestCursorOf(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated := created.Add(time.Hour)
+	task := Task{ID: 5, Title: "Task", CreatedAt: created, UpdatedAt: updated}
+
+	assert.Equal(t, &TaskCursor{ID: 5, Time: created}, cursorOf(SortCreatedAt, task))
+	assert.Equal(t, &TaskCursor{ID: 5, Time: updated}, cursorOf(SortUpdatedAt, task))
+	assert.Equal(t, &TaskCursor{ID: 5, Title: "Task"}, cursorOf(SortTitle, task))
+}
@@ -1,12 +1,16 @@
-// Code generated by mockery v2.52.3. DO NOT EDIT.
+// Code generated by mockery v2.53.7. DO NOT EDIT.
 
 package mocks
 
 import (
 	context "context"
-	repo "simple-service/internal/repo"
 
+	pgxpool "github.com/jackc/pgx/v5/pgxpool"
 	mock "github.com/stretchr/testify/mock"
+
+	repo "simple-service/internal/repo"
+
+	time "time"
 )
 
 // Repository is an autogenerated mock type for the Repository type
@@ -14,9 +18,62 @@ type Repository struct {
 	mock.Mock
 }
 
-// CreateTask provides a mock function with given fields: ctx, task
-func (_m *Repository) CreateTask(ctx context.Context, task repo.Task) (int, error) {
-	ret := _m.Called(ctx, task)
+// ClaimReminders provides a mock function with given fields: ctx, limit, lease
+func (_m *Repository) ClaimReminders(ctx context.Context, limit int, lease time.Duration) ([]repo.Reminder, error) {
+	ret := _m.Called(ctx, limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimReminders")
+	}
+
+	var r0 []repo.Reminder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) ([]repo.Reminder, error)); ok {
+		return rf(ctx, limit, lease)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) []repo.Reminder); ok {
+		r0 = rf(ctx, limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.Reminder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = rf(ctx, limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Close provides a mock function with no fields
+func (_m *Repository) Close() {
+	_m.Called()
+}
+
+// CompleteReminder provides a mock function with given fields: ctx, reminder
+func (_m *Repository) CompleteReminder(ctx context.Context, reminder repo.Reminder) error {
+	ret := _m.Called(ctx, reminder)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteReminder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, repo.Reminder) error); ok {
+		r0 = rf(ctx, reminder)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTask provides a mock function with given fields: ctx, owner, task
+func (_m *Repository) CreateTask(ctx context.Context, owner string, task repo.Task) (int, error) {
+	ret := _m.Called(ctx, owner, task)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateTask")
@@ -24,17 +81,17 @@ func (_m *Repository) CreateTask(ctx context.Context, task repo.Task) (int, erro
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, repo.Task) (int, error)); ok {
-		return rf(ctx, task)
+	if rf, ok := ret.Get(0).(func(context.Context, string, repo.Task) (int, error)); ok {
+		return rf(ctx, owner, task)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, repo.Task) int); ok {
-		r0 = rf(ctx, task)
+	if rf, ok := ret.Get(0).(func(context.Context, string, repo.Task) int); ok {
+		r0 = rf(ctx, owner, task)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, repo.Task) error); ok {
-		r1 = rf(ctx, task)
+	if rf, ok := ret.Get(1).(func(context.Context, string, repo.Task) error); ok {
+		r1 = rf(ctx, owner, task)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -42,6 +99,412 @@ func (_m *Repository) CreateTask(ctx context.Context, task repo.Task) (int, erro
 	return r0, r1
 }
 
+// DeleteAnyTask provides a mock function with given fields: ctx, taskID, version
+func (_m *Repository) DeleteAnyTask(ctx context.Context, taskID int, version *int) error {
+	ret := _m.Called(ctx, taskID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAnyTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *int) error); ok {
+		r0 = rf(ctx, taskID, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteIdempotencyKey provides a mock function with given fields: ctx, owner, key
+func (_m *Repository) DeleteIdempotencyKey(ctx context.Context, owner string, key string) error {
+	ret := _m.Called(ctx, owner, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, owner, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTask provides a mock function with given fields: ctx, owner, taskID, version
+func (_m *Repository) DeleteTask(ctx context.Context, owner string, taskID int, version *int) error {
+	ret := _m.Called(ctx, owner, taskID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, *int) error); ok {
+		r0 = rf(ctx, owner, taskID, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetTask provides a mock function with given fields: ctx, owner, taskID
+func (_m *Repository) GetTask(ctx context.Context, owner string, taskID int) (*repo.Task, error) {
+	ret := _m.Called(ctx, owner, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTask")
+	}
+
+	var r0 *repo.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*repo.Task, error)); ok {
+		return rf(ctx, owner, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *repo.Task); ok {
+		r0 = rf(ctx, owner, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repo.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, owner, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTasks provides a mock function with given fields: ctx, owner, filter
+func (_m *Repository) ListTasks(ctx context.Context, owner string, filter repo.TaskFilter) (*repo.TaskPage, error) {
+	ret := _m.Called(ctx, owner, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTasks")
+	}
+
+	var r0 *repo.TaskPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, repo.TaskFilter) (*repo.TaskPage, error)); ok {
+		return rf(ctx, owner, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, repo.TaskFilter) *repo.TaskPage); ok {
+		r0 = rf(ctx, owner, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repo.TaskPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, repo.TaskFilter) error); ok {
+		r1 = rf(ctx, owner, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationVersion provides a mock function with given fields: ctx
+func (_m *Repository) MigrationVersion(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MigrationVersion")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchTask provides a mock function with given fields: ctx, owner, taskID, patch, version
+func (_m *Repository) PatchTask(ctx context.Context, owner string, taskID int, patch repo.TaskPatch, version *int) (int, error) {
+	ret := _m.Called(ctx, owner, taskID, patch, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchTask")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, repo.TaskPatch, *int) (int, error)); ok {
+		return rf(ctx, owner, taskID, patch, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, repo.TaskPatch, *int) int); ok {
+		r0 = rf(ctx, owner, taskID, patch, version)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, repo.TaskPatch, *int) error); ok {
+		r1 = rf(ctx, owner, taskID, patch, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Ping provides a mock function with given fields: ctx
+func (_m *Repository) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeIdempotencyKeys provides a mock function with given fields: ctx
+func (_m *Repository) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeIdempotencyKeys")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseReminder provides a mock function with given fields: ctx, reminder
+func (_m *Repository) ReleaseReminder(ctx context.Context, reminder repo.Reminder) error {
+	ret := _m.Called(ctx, reminder)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseReminder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, repo.Reminder) error); ok {
+		r0 = rf(ctx, reminder)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenewReminder provides a mock function with given fields: ctx, reminder
+func (_m *Repository) RenewReminder(ctx context.Context, reminder repo.Reminder) (repo.Reminder, error) {
+	ret := _m.Called(ctx, reminder)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenewReminder")
+	}
+
+	var r0 repo.Reminder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repo.Reminder) (repo.Reminder, error)); ok {
+		return rf(ctx, reminder)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repo.Reminder) repo.Reminder); ok {
+		r0 = rf(ctx, reminder)
+	} else {
+		r0 = ret.Get(0).(repo.Reminder)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repo.Reminder) error); ok {
+		r1 = rf(ctx, reminder)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReserveIdempotencyKey provides a mock function with given fields: ctx, owner, key, requestHash, ttl, lockTimeout
+func (_m *Repository) ReserveIdempotencyKey(ctx context.Context, owner string, key string, requestHash string, ttl time.Duration, lockTimeout time.Duration) (*repo.IdempotencyRecord, error) {
+	ret := _m.Called(ctx, owner, key, requestHash, ttl, lockTimeout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveIdempotencyKey")
+	}
+
+	var r0 *repo.IdempotencyRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Duration, time.Duration) (*repo.IdempotencyRecord, error)); ok {
+		return rf(ctx, owner, key, requestHash, ttl, lockTimeout)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Duration, time.Duration) *repo.IdempotencyRecord); ok {
+		r0 = rf(ctx, owner, key, requestHash, ttl, lockTimeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repo.IdempotencyRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, time.Duration, time.Duration) error); ok {
+		r1 = rf(ctx, owner, key, requestHash, ttl, lockTimeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveIdempotentResponse provides a mock function with given fields: ctx, owner, key, statusCode, response
+func (_m *Repository) SaveIdempotentResponse(ctx context.Context, owner string, key string, statusCode int, response []byte) error {
+	ret := _m.Called(ctx, owner, key, statusCode, response)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveIdempotentResponse")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, []byte) error); ok {
+		r0 = rf(ctx, owner, key, statusCode, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchTasks provides a mock function with given fields: ctx, owner, query, lang, limit
+func (_m *Repository) SearchTasks(ctx context.Context, owner string, query string, lang repo.SearchLanguage, limit int) ([]repo.TaskSearchResult, error) {
+	ret := _m.Called(ctx, owner, query, lang, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchTasks")
+	}
+
+	var r0 []repo.TaskSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, repo.SearchLanguage, int) ([]repo.TaskSearchResult, error)); ok {
+		return rf(ctx, owner, query, lang, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, repo.SearchLanguage, int) []repo.TaskSearchResult); ok {
+		r0 = rf(ctx, owner, query, lang, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.TaskSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, repo.SearchLanguage, int) error); ok {
+		r1 = rf(ctx, owner, query, lang, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Stat provides a mock function with no fields
+func (_m *Repository) Stat() *pgxpool.Stat {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stat")
+	}
+
+	var r0 *pgxpool.Stat
+	if rf, ok := ret.Get(0).(func() *pgxpool.Stat); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pgxpool.Stat)
+		}
+	}
+
+	return r0
+}
+
+// UpdateTask provides a mock function with given fields: ctx, owner, taskID, task, version
+func (_m *Repository) UpdateTask(ctx context.Context, owner string, taskID int, task repo.Task, version *int) (int, error) {
+	ret := _m.Called(ctx, owner, taskID, task, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTask")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, repo.Task, *int) (int, error)); ok {
+		return rf(ctx, owner, taskID, task, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, repo.Task, *int) int); ok {
+		r0 = rf(ctx, owner, taskID, task, version)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, repo.Task, *int) error); ok {
+		r1 = rf(ctx, owner, taskID, task, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTaskStatus provides a mock function with given fields: ctx, owner, taskID, from, to
+func (_m *Repository) UpdateTaskStatus(ctx context.Context, owner string, taskID int, from repo.TaskStatus, to repo.TaskStatus) error {
+	ret := _m.Called(ctx, owner, taskID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTaskStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, repo.TaskStatus, repo.TaskStatus) error); ok {
+		r0 = rf(ctx, owner, taskID, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRepository(t interface {
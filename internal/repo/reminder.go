@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+)
+
+// Напоминания о сроках задач. Реплика забирает напоминание на время аренды, продлевает её
+// перед отправкой и отмечает напоминание отправленным только после доставки. Строки,
+// заблокированные другой репликой, пропускаются, а напоминание реплики, которая упала до отметки,
+// после окончания аренды забирает другая. Аренда опознаётся по reminder_claimed_at, поэтому
+// реплика, чью аренду уже забрали, не может ни продлить её, ни отметить напоминание.
+// Доставка выходит «хотя бы один раз»: повтор возможен, если реплика упала или потеряла БД
+// между доставкой и отметкой либо получатель не уложился в аренду, игнорируя отмену контекста
+
+const (
+	reminderConstraint = "tasks_remind_before_due"
+
+	claimRemindersQuery = `UPDATE tasks SET reminder_claimed_at=now()
+		WHERE id IN (
+			SELECT id FROM tasks
+			WHERE reminded_at IS NULL AND remind_at <= now() AND status <> 'done'
+				AND (reminder_claimed_at IS NULL OR reminder_claimed_at <= now() - make_interval(secs => $2))
+			ORDER BY remind_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, (SELECT name FROM users WHERE users.id=tasks.owner_id), title, due_at, remind_at, reminder_claimed_at`
+	// Перенос напоминания сбрасывает аренду, поэтому совпадение reminder_claimed_at означает,
+	// что напоминание всё ещё наше и не изменилось. Завершённой задаче напоминание уже не нужно
+	renewReminderQuery = `UPDATE tasks SET reminder_claimed_at=now()
+		WHERE id=($1) AND reminder_claimed_at=($2) AND reminded_at IS NULL AND status <> 'done'
+		RETURNING reminder_claimed_at`
+	completeReminderQuery = `UPDATE tasks SET reminded_at=now(), reminder_claimed_at=NULL
+		WHERE id=($1) AND reminder_claimed_at=($2) AND reminded_at IS NULL`
+	releaseReminderQuery = `UPDATE tasks SET reminder_claimed_at=NULL
+		WHERE id=($1) AND reminder_claimed_at=($2) AND reminded_at IS NULL`
+)
+
+// ClaimReminders - до limit наступивших и ещё не отправленных напоминаний по незавершённым задачам.
+// Напоминания забираются на время lease, после него их снова сможет забрать любая реплика
+func (r *repository) ClaimReminders(ctx context.Context, limit int, lease time.Duration) ([]Reminder, error) {
+	rows, err := r.pool.Query(ctx, claimRemindersQuery, limit, lease.Seconds())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to claim reminders")
+	}
+	defer rows.Close()
+
+	var reminders []Reminder
+	for rows.Next() {
+		var rem Reminder
+		if err := rows.Scan(&rem.TaskID, &rem.Owner, &rem.Title, &rem.DueAt, &rem.RemindAt, &rem.ClaimedAt); err != nil {
+			return nil, errors.Wrap(err, "failed to scan reminder")
+		}
+		reminders = append(reminders, rem)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to claim reminders")
+	}
+	return reminders, nil
+}
+
+// RenewReminder - продление аренды на полный срок. ErrReminderLost, если аренду забрала другая реплика,
+// напоминание перенесли или задачу завершили
+func (r *repository) RenewReminder(ctx context.Context, reminder Reminder) (Reminder, error) {
+	err := r.pool.QueryRow(ctx, renewReminderQuery, reminder.TaskID, reminder.ClaimedAt).Scan(&reminder.ClaimedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Reminder{}, ErrReminderLost
+	}
+	if err != nil {
+		return Reminder{}, errors.Wrap(err, "failed to renew reminder")
+	}
+	return reminder, nil
+}
+
+// CompleteReminder - отметка о доставке, после неё напоминание больше не забирается.
+// ErrReminderLost, если аренду уже забрала другая реплика
+func (r *repository) CompleteReminder(ctx context.Context, reminder Reminder) error {
+	tag, err := r.pool.Exec(ctx, completeReminderQuery, reminder.TaskID, reminder.ClaimedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to complete reminder")
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrReminderLost
+	}
+	return nil
+}
+
+// ReleaseReminder - досрочное окончание аренды, чтобы напоминание забрали снова
+func (r *repository) ReleaseReminder(ctx context.Context, reminder Reminder) error {
+	if _, err := r.pool.Exec(ctx, releaseReminderQuery, reminder.TaskID, reminder.ClaimedAt); err != nil {
+		return errors.Wrap(err, "failed to release reminder")
+	}
+	return nil
+}
+
+// isReminderViolation - запрос нарушил ограничение на время напоминания
+func isReminderViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.ConstraintName == reminderConstraint
+}
@@ -0,0 +1,150 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminders(t *testing.T) {
+	ctx := context.Background()
+	const lease = time.Minute
+	now := time.Now()
+	due := now.Add(time.Hour)
+
+	// setup - репозиторий с напоминаниями, которые наступили в порядке id
+	setup := func(t *testing.T, count int) (*repository, []int) {
+		r := testRepository(t)
+		ids := make([]int, count)
+		for i := range ids {
+			remindAt := now.Add(time.Duration(i-count) * time.Minute)
+			ids[i] = createTestTask(t, r, "alice", Task{Title: "Task", DueAt: &due, RemindAt: &remindAt})
+		}
+		return r, ids
+	}
+	claim := func(t *testing.T, r *repository, limit int) []Reminder {
+		t.Helper()
+		reminders, err := r.ClaimReminders(ctx, limit, lease)
+		require.NoError(t, err)
+		return reminders
+	}
+	taskIDs := func(reminders []Reminder) []int {
+		ids := make([]int, 0, len(reminders))
+		for _, rem := range reminders {
+			ids = append(ids, rem.TaskID)
+		}
+		return ids
+	}
+	// expire - окончание аренды напоминания без участия реплики, которая его забрала
+	expire := func(t *testing.T, r *repository, id int) {
+		t.Helper()
+		_, err := r.pool.Exec(ctx, `UPDATE tasks SET reminder_claimed_at=reminder_claimed_at - make_interval(secs => $2) WHERE id=$1`,
+			id, (lease + time.Second).Seconds())
+		require.NoError(t, err)
+	}
+
+	t.Run("забираются только наступившие напоминания незавершённых задач", func(t *testing.T) {
+		r, ids := setup(t, 3)
+		later := now.Add(time.Minute)
+		createTestTask(t, r, "alice", Task{Title: "Later", DueAt: &due, RemindAt: &later})
+		createTestTask(t, r, "alice", Task{Title: "No reminder", DueAt: &due})
+		require.NoError(t, r.UpdateTaskStatus(ctx, "alice", ids[2], StatusNew, StatusDone))
+
+		reminders := claim(t, r, 10)
+		assert.Equal(t, ids[:2], taskIDs(reminders))
+		assert.Equal(t, "alice", reminders[0].Owner)
+		assert.False(t, reminders[0].ClaimedAt.IsZero())
+	})
+
+	t.Run("забранное напоминание не видно до конца аренды", func(t *testing.T) {
+		r, ids := setup(t, 3)
+		assert.Equal(t, ids[:2], taskIDs(claim(t, r, 2)))
+		assert.Equal(t, ids[2:], taskIDs(claim(t, r, 2)))
+		assert.Empty(t, claim(t, r, 2))
+	})
+
+	t.Run("заблокированные строки пропускаются", func(t *testing.T) {
+		r, ids := setup(t, 2)
+		tx, err := r.pool.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+		_, err = tx.Exec(ctx, `SELECT id FROM tasks WHERE id=$1 FOR UPDATE`, ids[0])
+		require.NoError(t, err)
+
+		assert.Equal(t, ids[1:], taskIDs(claim(t, r, 10)))
+	})
+
+	t.Run("отправленное напоминание больше не забирается", func(t *testing.T) {
+		r, _ := setup(t, 1)
+		reminders := claim(t, r, 10)
+		require.Len(t, reminders, 1)
+		require.NoError(t, r.CompleteReminder(ctx, reminders[0]))
+
+		expire(t, r, reminders[0].TaskID)
+		assert.Empty(t, claim(t, r, 10))
+	})
+
+	t.Run("возвращённое напоминание забирается снова", func(t *testing.T) {
+		r, ids := setup(t, 1)
+		reminders := claim(t, r, 10)
+		require.Len(t, reminders, 1)
+		require.NoError(t, r.ReleaseReminder(ctx, reminders[0]))
+
+		assert.Equal(t, ids, taskIDs(claim(t, r, 10)))
+	})
+
+	t.Run("после аренды напоминание забирает другая реплика", func(t *testing.T) {
+		r, ids := setup(t, 1)
+		stale := claim(t, r, 10)
+		require.Len(t, stale, 1)
+		expire(t, r, ids[0])
+
+		fresh := claim(t, r, 10)
+		assert.Equal(t, ids, taskIDs(fresh))
+
+		// Реплика с истёкшей арендой не может ни продлить её, ни отметить, ни вернуть напоминание
+		_, err := r.RenewReminder(ctx, stale[0])
+		assert.ErrorIs(t, err, ErrReminderLost)
+		assert.ErrorIs(t, r.CompleteReminder(ctx, stale[0]), ErrReminderLost)
+		require.NoError(t, r.ReleaseReminder(ctx, stale[0]))
+		assert.Empty(t, claim(t, r, 10))
+
+		assert.NoError(t, r.CompleteReminder(ctx, fresh[0]))
+	})
+
+	t.Run("продление начинает аренду заново", func(t *testing.T) {
+		r, ids := setup(t, 1)
+		claimed := claim(t, r, 10)
+		require.Len(t, claimed, 1)
+		expire(t, r, ids[0])
+
+		renewed, err := r.RenewReminder(ctx, claimed[0])
+		require.NoError(t, err)
+		assert.True(t, renewed.ClaimedAt.After(claimed[0].ClaimedAt))
+		assert.Empty(t, claim(t, r, 10))
+
+		assert.ErrorIs(t, r.CompleteReminder(ctx, claimed[0]), ErrReminderLost)
+		assert.NoError(t, r.CompleteReminder(ctx, renewed))
+	})
+
+	t.Run("перенесённое напоминание и завершённая задача не продлеваются", func(t *testing.T) {
+		r, ids := setup(t, 2)
+		reminders := claim(t, r, 10)
+		require.Len(t, reminders, 2)
+
+		remindAt := now.Add(-time.Second)
+		_, err := r.PatchTask(ctx, "alice", ids[0], TaskPatch{RemindAt: NullableTime{Time: &remindAt, Set: true}}, nil)
+		require.NoError(t, err)
+		require.NoError(t, r.UpdateTaskStatus(ctx, "alice", ids[1], StatusNew, StatusDone))
+
+		for _, rem := range reminders {
+			_, err := r.RenewReminder(ctx, rem)
+			assert.ErrorIs(t, err, ErrReminderLost)
+		}
+		// Перенесённое напоминание снова в очереди
+		assert.Equal(t, ids[:1], taskIDs(claim(t, r, 10)))
+	})
+}
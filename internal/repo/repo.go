@@ -3,8 +3,9 @@ package repo
 import (
 	"context"
 	"fmt"
-	"github.com/jackc/pgx/v5"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pkg/errors"
 
@@ -13,34 +14,101 @@ import (
 
 // Слой репозитория, здесь должны быть все методы, связанные с базой данных
 
-// SQL-запрос на вставку задачи
+// SQL-запросы для работы с задачами.
+// Все запросы ограничены задачами владельца, имя владельца всегда передаётся первым параметром
 const (
-	insertTaskQuery = `INSERT INTO tasks (title, description) VALUES ($1, $2) RETURNING id`
-	getTaskQuery    = `SELECT title, description FROM tasks WHERE id=($1)`
+	ownerIDQuery = `(SELECT id FROM users WHERE name=($1))`
+	// ownerCTE - id владельца $1, пользователь создаётся при первой записи, чтобы не заводить его отдельно.
+	// DO NOTHING не блокирует и не перезаписывает существующую строку, а её id берётся обычным SELECT.
+	// Если того же нового пользователя параллельно создаёт другой запрос, owner пуст и запрос повторяется
+	ownerCTE = `new_owner AS (
+			INSERT INTO users (name) VALUES ($1) ON CONFLICT (name) DO NOTHING RETURNING id
+		),
+		owner AS (
+			SELECT id FROM new_owner UNION ALL SELECT id FROM users WHERE name=($1)
+		)`
+	insertTaskQuery = `WITH ` + ownerCTE + `
+		INSERT INTO tasks (owner_id, title, description, due_at, remind_at) SELECT id, $2, $3, $4, $5 FROM owner RETURNING id`
+	taskColumns     = `id, title, description, status, created_at, updated_at, version, due_at, remind_at`
+	getTaskQuery    = `SELECT ` + taskColumns + ` FROM tasks WHERE owner_id=` + ownerIDQuery + ` AND id=($2)`
+	taskExistsQuery = `SELECT EXISTS (SELECT 1 FROM tasks WHERE owner_id=` + ownerIDQuery + ` AND id=($2))`
+	// Изменения применяются, только если версия совпадает с ожидаемой; NULL отключает проверку.
+	// При переносе напоминания отметка об отправке сбрасывается, чтобы оно пришло в новое время
+	updateTaskQuery = `UPDATE tasks SET title=($3), description=($4), due_at=($6), remind_at=($7),
+			reminded_at=CASE WHEN remind_at IS DISTINCT FROM $7 THEN NULL ELSE reminded_at END,
+			reminder_claimed_at=CASE WHEN remind_at IS DISTINCT FROM $7 THEN NULL ELSE reminder_claimed_at END,
+			version=version+1
+		WHERE owner_id=` + ownerIDQuery + ` AND id=($2) AND ($5::int IS NULL OR version=$5) RETURNING version`
+	// Срок и напоминание меняются по флагам $8 и $9, поэтому переданный NULL очищает их
+	patchTaskQuery = `UPDATE tasks SET title=COALESCE($3, title), description=COALESCE($4, description),
+			due_at=CASE WHEN $8::bool THEN $6::timestamptz ELSE due_at END,
+			remind_at=CASE WHEN $9::bool THEN $7::timestamptz ELSE remind_at END,
+			reminded_at=CASE WHEN $9::bool AND remind_at IS DISTINCT FROM $7 THEN NULL ELSE reminded_at END,
+			reminder_claimed_at=CASE WHEN $9::bool AND remind_at IS DISTINCT FROM $7 THEN NULL ELSE reminder_claimed_at END,
+			version=version+1
+		WHERE owner_id=` + ownerIDQuery + ` AND id=($2) AND ($5::int IS NULL OR version=$5) RETURNING version`
+	deleteTaskQuery = `DELETE FROM tasks
+		WHERE owner_id=` + ownerIDQuery + ` AND id=($2) AND ($3::int IS NULL OR version=$3) RETURNING id`
+	// Удаление без учёта владельца, доступно только администратору
+	deleteAnyTaskQuery = `DELETE FROM tasks WHERE id=($1) AND ($2::int IS NULL OR version=$2) RETURNING id`
+	anyTaskExistsQuery = `SELECT EXISTS (SELECT 1 FROM tasks WHERE id=($1))`
+
+	migrationVersionQuery = `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`
+	// Статус меняется только если он не изменился с момента чтения
+	updateStatusQuery = `UPDATE tasks SET status=($4), version=version+1
+		WHERE owner_id=` + ownerIDQuery + ` AND id=($2) AND status=($3) RETURNING id`
+)
+
+var (
+	// ErrTaskNotFound - задача с указанным id не существует
+	ErrTaskNotFound = errors.New("task not found")
+	// ErrStatusChanged - статус задачи изменился между чтением и обновлением
+	ErrStatusChanged = errors.New("task status was changed concurrently")
+	// ErrVersionMismatch - версия задачи не совпадает с ожидаемой
+	ErrVersionMismatch = errors.New("task version does not match")
+	// ErrInvalidReminder - напоминание без срока или позже срока выполнения
+	ErrInvalidReminder = errors.New("remind_at must be set together with due_at and not later than it")
+	// ErrReminderLost - аренда напоминания закончилась, и его забрала другая реплика, либо оно уже не нужно
+	ErrReminderLost = errors.New("reminder claim was lost")
 )
 
 type repository struct {
 	pool *pgxpool.Pool
 }
 
-// Repository - интерфейс с методом создания задачи
+// Repository - интерфейс с методами для работы с задачами.
+// owner - имя пользователя, которому принадлежат задачи; чужие задачи не видны
 type Repository interface {
-	GetTask(ctx context.Context, taskID int) (*Task, error)
-	CreateTask(ctx context.Context, task Task) (int, error) // Создание задачи
+	GetTask(ctx context.Context, owner string, taskID int) (*Task, error)
+	ListTasks(ctx context.Context, owner string, filter TaskFilter) (*TaskPage, error)
+	SearchTasks(ctx context.Context, owner, query string, lang SearchLanguage, limit int) ([]TaskSearchResult, error)
+	CreateTask(ctx context.Context, owner string, task Task) (int, error) // Создание задачи
+	// version - ожидаемая версия задачи, nil отключает проверку. Update и Patch возвращают новую версию
+	UpdateTask(ctx context.Context, owner string, taskID int, task Task, version *int) (int, error)
+	PatchTask(ctx context.Context, owner string, taskID int, patch TaskPatch, version *int) (int, error)
+	DeleteTask(ctx context.Context, owner string, taskID int, version *int) error
+	DeleteAnyTask(ctx context.Context, taskID int, version *int) error // Удаление задачи любого владельца
+	UpdateTaskStatus(ctx context.Context, owner string, taskID int, from, to TaskStatus) error
+	ReserveIdempotencyKey(ctx context.Context, owner, key, requestHash string, ttl, lockTimeout time.Duration) (*IdempotencyRecord, error)
+	SaveIdempotentResponse(ctx context.Context, owner, key string, statusCode int, response []byte) error
+	DeleteIdempotencyKey(ctx context.Context, owner, key string) error
+	PurgeIdempotencyKeys(ctx context.Context) (int64, error)                                // Удаление просроченных ключей идемпотентности
+	ClaimReminders(ctx context.Context, limit int, lease time.Duration) ([]Reminder, error) // Наступившие напоминания, забираются на время lease
+	RenewReminder(ctx context.Context, reminder Reminder) (Reminder, error)                 // Продление аренды напоминания перед отправкой
+	CompleteReminder(ctx context.Context, reminder Reminder) error                          // Отметка об отправке напоминания
+	ReleaseReminder(ctx context.Context, reminder Reminder) error                           // Возврат напоминания, которое не удалось отправить
+	Ping(ctx context.Context) error                                                         // Проверка доступности БД
+	MigrationVersion(ctx context.Context) (int, error)                                      // Версия последней применённой миграции
+	Stat() *pgxpool.Stat                                                                    // Статистика пула соединений
+	Close()                                                                                 // Закрытие пула соединений
 }
 
 // NewRepository - создание нового экземпляра репозитория с подключением к PostgreSQL
 func NewRepository(ctx context.Context, cfg config.PostgreSQL) (Repository, error) {
 	// Формируем строку подключения
 	connString := fmt.Sprintf(
-		`user=%s password=%s host=%s port=%d dbname=%s sslmode=%s 
-        pool_max_conns=%d pool_max_conn_lifetime=%s pool_max_conn_idle_time=%s`,
-		cfg.User,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Name,
-		cfg.SSLMode,
+		`%s pool_max_conns=%d pool_max_conn_lifetime=%s pool_max_conn_idle_time=%s`,
+		cfg.ConnString(),
 		cfg.PoolMaxConns,
 		cfg.PoolMaxConnLifetime.String(),
 		cfg.PoolMaxConnIdleTime.String(),
@@ -55,6 +123,13 @@ func NewRepository(ctx context.Context, cfg config.PostgreSQL) (Repository, erro
 	// Оптимизация выполнения запросов (кеширование запросов)
 	config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeCacheDescribe
 
+	// Span OpenTelemetry на каждый запрос
+	config.ConnConfig.Tracer = queryTracer{}
+
+	// created_at и updated_at - TIMESTAMP без зоны, заполняемые now(). Зона сессии
+	// фиксируется в UTC, чтобы значения и фильтры не зависели от настроек сервера БД
+	config.ConnConfig.RuntimeParams["timezone"] = "UTC"
+
 	// Создаём пул соединений с базой данных
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
@@ -64,21 +139,189 @@ func NewRepository(ctx context.Context, cfg config.PostgreSQL) (Repository, erro
 	return &repository{pool}, nil
 }
 
+// Ping - проверка, что БД отвечает
+func (r *repository) Ping(ctx context.Context) error {
+	return r.pool.Ping(ctx)
+}
+
+// MigrationVersion - версия последней миграции из schema_migrations
+func (r *repository) MigrationVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := r.pool.QueryRow(ctx, migrationVersionQuery).Scan(&version); err != nil {
+		return 0, errors.Wrap(err, "failed to get migration version")
+	}
+	return version, nil
+}
+
+// Stat - текущая статистика пула соединений
+func (r *repository) Stat() *pgxpool.Stat {
+	return r.pool.Stat()
+}
+
+// Close - закрытие всех соединений пула, ожидает возврата занятых соединений
+func (r *repository) Close() {
+	r.pool.Close()
+}
+
 // CreateTask - вставка новой задачи в таблицу tasks
-func (r *repository) CreateTask(ctx context.Context, task Task) (int, error) {
+func (r *repository) CreateTask(ctx context.Context, owner string, task Task) (int, error) {
 	var id int
-	err := r.pool.QueryRow(ctx, insertTaskQuery, task.Title, task.Description).Scan(&id)
+	var err error
+	// Второй попытке нужен новый снимок, в котором виден пользователь, созданный параллельным запросом
+	for attempt := 0; attempt < 2; attempt++ {
+		err = r.pool.QueryRow(ctx, insertTaskQuery, owner, task.Title, task.Description, task.DueAt, task.RemindAt).Scan(&id)
+		if !errors.Is(err, pgx.ErrNoRows) {
+			break
+		}
+	}
+	if isReminderViolation(err) {
+		return 0, ErrInvalidReminder
+	}
 	if err != nil {
 		return 0, errors.Wrap(err, "failed to insert task")
 	}
 	return id, nil
 }
 
-func (r *repository) GetTask(ctx context.Context, taskID int) (*Task, error) {
+// scanTask - чтение строки с колонками taskColumns, подходит и для pgx.Row, и для pgx.Rows.
+// extra - приёмники для колонок, следующих в запросе после taskColumns
+func scanTask(row pgx.Row, extra ...any) (Task, error) {
 	var task Task
-	err := r.pool.QueryRow(ctx, getTaskQuery, taskID).Scan(&task.Title, &task.Description)
+	dest := append([]any{
+		&task.ID, &task.Title, &task.Description, &task.Status, &task.CreatedAt, &task.UpdatedAt, &task.Version,
+		&task.DueAt, &task.RemindAt,
+	}, extra...)
+	err := row.Scan(dest...)
+	return task, err
+}
+
+// GetTask - получение задачи по id
+func (r *repository) GetTask(ctx context.Context, owner string, taskID int) (*Task, error) {
+	task, err := scanTask(r.pool.QueryRow(ctx, getTaskQuery, owner, taskID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTaskNotFound
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get task")
 	}
 	return &task, nil
 }
+
+// ListTasks - получение страницы задач с учётом фильтров и сортировки
+func (r *repository) ListTasks(ctx context.Context, owner string, filter TaskFilter) (*TaskPage, error) {
+	query, args, err := buildListQuery(owner, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build list query")
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list tasks")
+	}
+	defer rows.Close()
+
+	tasks := make([]Task, 0, filter.Limit+1)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan task")
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to list tasks")
+	}
+
+	page := &TaskPage{Tasks: tasks}
+	if len(tasks) > filter.Limit {
+		page.Tasks = tasks[:filter.Limit]
+		page.Next = cursorOf(filter.Sort, page.Tasks[len(page.Tasks)-1])
+	}
+	return page, nil
+}
+
+// UpdateTask - полная замена полей задачи
+func (r *repository) UpdateTask(ctx context.Context, owner string, taskID int, task Task, version *int) (int, error) {
+	var newVersion int
+	err := r.pool.QueryRow(ctx, updateTaskQuery, owner, taskID, task.Title, task.Description, version,
+		task.DueAt, task.RemindAt).Scan(&newVersion)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, r.notUpdatedError(ctx, taskExistsQuery, owner, taskID)
+	}
+	if isReminderViolation(err) {
+		return 0, ErrInvalidReminder
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to update task")
+	}
+	return newVersion, nil
+}
+
+// PatchTask - обновление только переданных полей задачи
+func (r *repository) PatchTask(ctx context.Context, owner string, taskID int, patch TaskPatch, version *int) (int, error) {
+	var newVersion int
+	err := r.pool.QueryRow(ctx, patchTaskQuery, owner, taskID, patch.Title, patch.Description, version,
+		patch.DueAt.Time, patch.RemindAt.Time, patch.DueAt.Set, patch.RemindAt.Set).Scan(&newVersion)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, r.notUpdatedError(ctx, taskExistsQuery, owner, taskID)
+	}
+	if isReminderViolation(err) {
+		return 0, ErrInvalidReminder
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to patch task")
+	}
+	return newVersion, nil
+}
+
+// DeleteTask - удаление задачи по id
+func (r *repository) DeleteTask(ctx context.Context, owner string, taskID int, version *int) error {
+	var id int
+	err := r.pool.QueryRow(ctx, deleteTaskQuery, owner, taskID, version).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.notUpdatedError(ctx, taskExistsQuery, owner, taskID)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to delete task")
+	}
+	return nil
+}
+
+// DeleteAnyTask - удаление задачи по id без проверки владельца
+func (r *repository) DeleteAnyTask(ctx context.Context, taskID int, version *int) error {
+	var id int
+	err := r.pool.QueryRow(ctx, deleteAnyTaskQuery, taskID, version).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.notUpdatedError(ctx, anyTaskExistsQuery, taskID)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to delete task")
+	}
+	return nil
+}
+
+// notUpdatedError - причина, по которой запрос не изменил ни одной строки:
+// задачи нет или её версия не совпала с ожидаемой. existsQuery проверяет наличие задачи
+func (r *repository) notUpdatedError(ctx context.Context, existsQuery string, args ...any) error {
+	var exists bool
+	if err := r.pool.QueryRow(ctx, existsQuery, args...).Scan(&exists); err != nil {
+		return errors.Wrap(err, "failed to check task")
+	}
+	if exists {
+		return ErrVersionMismatch
+	}
+	return ErrTaskNotFound
+}
+
+// UpdateTaskStatus - смена статуса задачи с from на to
+func (r *repository) UpdateTaskStatus(ctx context.Context, owner string, taskID int, from, to TaskStatus) error {
+	var id int
+	err := r.pool.QueryRow(ctx, updateStatusQuery, owner, taskID, from, to).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrStatusChanged
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to update task status")
+	}
+	return nil
+}
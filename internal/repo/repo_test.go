@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"simple-service/internal/config"
+)
+
+func TestNewRepositoryTimeZone(t *testing.T) {
+	// Пул подключается лениво, поэтому БД для проверки конфигурации не нужна
+	r, err := NewRepository(context.Background(), config.PostgreSQL{
+		Host: "localhost", Port: 5432, Name: "tasks", User: "user", Password: "secret",
+		SSLMode: "disable", PoolMaxConns: 1,
+	})
+	require.NoError(t, err)
+	pool := r.(*repository).pool
+	t.Cleanup(pool.Close)
+
+	assert.Equal(t, "UTC", pool.Config().ConnConfig.RuntimeParams["timezone"])
+}
+
+func TestCreateTaskOwner(t *testing.T) {
+	r := testRepository(t)
+	ctx := context.Background()
+
+	first := createTestTask(t, r, "alice", Task{Title: "First"})
+	second := createTestTask(t, r, "alice", Task{Title: "Second"})
+	createTestTask(t, r, "bob", Task{Title: "Bob's"})
+
+	// Пользователь заводится при первой задаче и не создаётся повторно
+	var users int
+	require.NoError(t, r.pool.QueryRow(ctx, `SELECT count(*) FROM users WHERE name='alice'`).Scan(&users))
+	assert.Equal(t, 1, users)
+
+	page, err := r.ListTasks(ctx, "alice", TaskFilter{Sort: SortCreatedAt, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Tasks, 2)
+	assert.Equal(t, first, page.Tasks[0].ID)
+	assert.Equal(t, second, page.Tasks[1].ID)
+}
@@ -0,0 +1,84 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Полнотекстовый поиск задач
+
+// searchConfigs - конфигурация PostgreSQL и столбец tsvector для каждого языка
+var searchConfigs = map[SearchLanguage]struct {
+	config string
+	column string
+}{
+	LanguageRussian: {config: "russian", column: "search_ru"},
+	LanguageEnglish: {config: "english", column: "search_en"},
+}
+
+// ts_headline не экранирует текст задачи, поэтому совпадения отмечаются управляющими символами,
+// которые убираются из исходного текста, а HTML собирается уже после экранирования
+const headlineOptions = `StartSel=\x01, StopSel=\x02`
+
+var highlightReplacer = strings.NewReplacer("\x01", "<b>", "\x02", "</b>")
+
+// highlightHTML - фрагмент с отметками ts_headline в виде безопасного HTML
+func highlightHTML(headline string) string {
+	return highlightReplacer.Replace(html.EscapeString(headline))
+}
+
+// searchQueries - запросы поиска для каждого языка. Конфигурация подставляется константой,
+// иначе PostgreSQL не сможет использовать GIN-индекс по столбцу языка
+var searchQueries = buildSearchQueries()
+
+func buildSearchQueries() map[SearchLanguage]string {
+	queries := make(map[SearchLanguage]string, len(searchConfigs))
+	for lang, c := range searchConfigs {
+		queries[lang] = fmt.Sprintf(`SELECT %[1]s,
+				ts_rank(%[3]s, q) AS rank,
+				ts_headline('%[2]s', translate(title, E'\x01\x02', ''), q, E'%[5]s'),
+				ts_headline('%[2]s', translate(coalesce(description, ''), E'\x01\x02', ''), q,
+					E'%[5]s, MaxFragments=2, MinWords=5, MaxWords=20')
+			FROM tasks, websearch_to_tsquery('%[2]s', $2) q
+			WHERE owner_id=%[4]s AND %[3]s @@ q
+			ORDER BY rank DESC, id
+			LIMIT $3`, taskColumns, c.config, c.column, ownerIDQuery, headlineOptions)
+	}
+	return queries
+}
+
+// SearchTasks - поиск задач владельца по словам из заголовка и описания, самые релевантные первыми
+func (r *repository) SearchTasks(
+	ctx context.Context, owner, query string, lang SearchLanguage, limit int,
+) ([]TaskSearchResult, error) {
+	sql, ok := searchQueries[lang]
+	if !ok {
+		return nil, fmt.Errorf("unknown search language %q", lang)
+	}
+
+	rows, err := r.pool.Query(ctx, sql, owner, query, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search tasks")
+	}
+	defer rows.Close()
+
+	results := make([]TaskSearchResult, 0)
+	for rows.Next() {
+		var res TaskSearchResult
+		res.Task, err = scanTask(rows, &res.Rank, &res.Highlight.Title, &res.Highlight.Description)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan task")
+		}
+		res.Highlight.Title = highlightHTML(res.Highlight.Title)
+		res.Highlight.Description = highlightHTML(res.Highlight.Description)
+		results = append(results, res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to search tasks")
+	}
+	return results, nil
+}
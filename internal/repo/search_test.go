@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighlightHTML(t *testing.T) {
+	// Текст задачи экранируется, тегами становятся только отметки ts_headline
+	assert.Equal(t, `&lt;img src=x onerror=alert(1)&gt; <b>отчёт</b> &amp; план`,
+		highlightHTML("<img src=x onerror=alert(1)> \x01отчёт\x02 & план"))
+	assert.Empty(t, highlightHTML(""))
+}
+
+func TestSearchTasks(t *testing.T) {
+	r := testRepository(t)
+	ctx := context.Background()
+
+	inTitle := createTestTask(t, r, "alice", Task{Title: "Квартальный отчёт", Description: "Собрать цифры"})
+	inDescription := createTestTask(t, r, "alice", Task{Title: "Созвон", Description: "Обсудить отчёты отдела"})
+	createTestTask(t, r, "alice", Task{Title: "Купить молоко"})
+	createTestTask(t, r, "bob", Task{Title: "Отчёт Боба"})
+	english := createTestTask(t, r, "alice", Task{Title: "Weekly reports", Description: "Send to the team"})
+
+	t.Run("совпадение в заголовке выше совпадения в описании", func(t *testing.T) {
+		results, err := r.SearchTasks(ctx, "alice", "отчёт", LanguageRussian, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, inTitle, results[0].ID)
+		assert.Equal(t, inDescription, results[1].ID)
+		assert.Greater(t, results[0].Rank, results[1].Rank)
+		assert.Contains(t, results[0].Highlight.Title, "<b>отчёт</b>")
+		assert.Contains(t, results[1].Highlight.Description, "<b>отчёты</b>")
+	})
+
+	t.Run("стемминг выбранного языка", func(t *testing.T) {
+		results, err := r.SearchTasks(ctx, "alice", "report", LanguageEnglish, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, english, results[0].ID)
+	})
+
+	t.Run("лимит", func(t *testing.T) {
+		results, err := r.SearchTasks(ctx, "alice", "отчёт", LanguageRussian, 1)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, inTitle, results[0].ID)
+	})
+
+	t.Run("ничего не найдено", func(t *testing.T) {
+		results, err := r.SearchTasks(ctx, "alice", "отпуск", LanguageRussian, 10)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func TestSearchTasksHighlightEscaping(t *testing.T) {
+	r := testRepository(t)
+	// Разметка и отметки ts_headline в тексте задачи не должны стать тегами
+	createTestTask(t, r, "alice", Task{Title: "<script>alert(1)</script> отчёт \x01x\x02"})
+
+	results, err := r.SearchTasks(context.Background(), "alice", "отчёт", LanguageRussian, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	highlight := results[0].Highlight.Title
+	assert.Contains(t, highlight, "<b>отчёт</b>")
+	assert.NotContains(t, highlight, "<script")
+	assert.NotContains(t, highlight, "<b>x</b>")
+}
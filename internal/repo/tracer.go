@@ -0,0 +1,49 @@
+package repo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"simple-service/internal/tracing"
+)
+
+// queryTracer - span на каждый SQL-запрос пула, подключается через pgx.ConnConfig.Tracer
+type queryTracer struct{}
+
+// TraceQueryStart - начало span запроса, вызывается pgx перед отправкой запроса
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, _ = tracing.Tracer().Start(ctx, "postgresql "+queryOperation(data.SQL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBQueryText(data.SQL),
+		),
+	)
+	return ctx
+}
+
+// TraceQueryEnd - завершение span запроса. Отсутствие строк ошибкой не считается,
+// репозиторий превращает его в доменную ошибку
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+	span.End()
+}
+
+// queryOperation - первое ключевое слово запроса для имени span
+func queryOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
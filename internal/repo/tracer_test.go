@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestQueryTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	tests := []struct {
+		name       string
+		sql        string
+		err        error
+		wantName   string
+		wantStatus codes.Code
+	}{
+		{
+			name:       "Успешный запрос",
+			sql:        getTaskQuery,
+			wantName:   "postgresql SELECT",
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "Отсутствие строк не ошибка",
+			sql:        deleteTaskQuery,
+			err:        pgx.ErrNoRows,
+			wantName:   "postgresql DELETE",
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "Ошибка запроса",
+			sql:        insertTaskQuery,
+			err:        errors.New("connection reset"),
+			wantName:   "postgresql WITH",
+			wantStatus: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tracer queryTracer
+			ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: tt.sql})
+			tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: tt.err})
+
+			spans := recorder.Ended()
+			require.NotEmpty(t, spans)
+			span := spans[len(spans)-1]
+			assert.Equal(t, tt.wantName, span.Name())
+			assert.Contains(t, span.Attributes(), semconv.DBQueryText(tt.sql))
+			assert.Equal(t, tt.wantStatus, span.Status().Code)
+		})
+	}
+}
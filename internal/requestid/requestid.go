@@ -0,0 +1,43 @@
+package requestid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Идентификатор запроса, общий для HTTP (X-Request-ID) и gRPC (x-request-id)
+
+// MaxLength - максимальная длина идентификатора, принимаемого от клиента
+const MaxLength = 128
+
+// Valid - идентификатор клиента принимается, только если он не раздует логи
+// и состоит из безопасных символов
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// New - случайный идентификатор из 16 байт в hex
+func New() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// FromClient - идентификатор клиента, если он допустим, иначе новый
+func FromClient(id string) string {
+	if Valid(id) {
+		return id
+	}
+	return New()
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"simple-service/internal/repo"
+)
+
+// Курсор пагинации списка задач, для клиента это непрозрачная строка
+
+var errCursorMismatch = errors.New("cursor was issued for another sort order")
+
+// pageCursor - содержимое курсора; сортировка сохраняется, чтобы курсор нельзя было применить к другой выборке
+type pageCursor struct {
+	Sort string `json:"sort"`
+	repo.TaskCursor
+}
+
+// encodeCursor - кодирование позиции в строку для ответа
+func encodeCursor(sort string, c repo.TaskCursor) string {
+	data, _ := json.Marshal(pageCursor{Sort: sort, TaskCursor: c})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor - разбор курсора из запроса, выданного для той же сортировки
+func decodeCursor(raw, sort string) (*repo.TaskCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if c.Sort != sort {
+		return nil, errCursorMismatch
+	}
+	return &c.TaskCursor, nil
+}
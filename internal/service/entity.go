@@ -1,7 +1,53 @@
 package service
 
+import (
+	"time"
+
+	"simple-service/internal/repo"
+)
+
 // TaskRequest - структура, представляющая тело запроса
 type TaskRequest struct {
-	Title       string `json:"title" validate:"required"`
-	Description string `json:"description"`
+	Title       string     `json:"title" validate:"required"`
+	Description string     `json:"description"`
+	DueAt       *time.Time `json:"due_at" validate:"required_with=RemindAt"`      // Срок выполнения
+	RemindAt    *time.Time `json:"remind_at" validate:"omitempty,ltefield=DueAt"` // Напоминание, не позже due_at
+}
+
+// TaskPatchRequest - тело запроса на частичное обновление задачи
+type TaskPatchRequest struct {
+	Title       *string           `json:"title" validate:"omitempty,min=1"`
+	Description *string           `json:"description"`
+	DueAt       repo.NullableTime `json:"due_at"`    // null очищает срок
+	RemindAt    repo.NullableTime `json:"remind_at"` // null убирает напоминание
+}
+
+// TransitionRequest - тело запроса на смену статуса задачи
+type TransitionRequest struct {
+	Status string `json:"status" validate:"required,oneof=new in_progress done"`
+	Reopen bool   `json:"reopen"`
+}
+
+// ListTasksRequest - параметры запроса списка задач
+type ListTasksRequest struct {
+	Limit       int    `query:"limit" validate:"omitempty,gte=1,lte=100"`
+	Sort        string `query:"sort" validate:"omitempty,oneof=created_at -created_at updated_at -updated_at title -title"`
+	Status      string `query:"status" validate:"omitempty,oneof=new in_progress done"`
+	CreatedFrom string `query:"created_from" validate:"omitempty,rfc3339"`
+	CreatedTo   string `query:"created_to" validate:"omitempty,rfc3339"`
+	Overdue     bool   `query:"overdue"` // Только незавершённые задачи с истёкшим сроком
+	Cursor      string `query:"cursor"`
+}
+
+// SearchTasksRequest - параметры полнотекстового поиска задач
+type SearchTasksRequest struct {
+	Query string `query:"q" validate:"required,max=200"`
+	Lang  string `query:"lang" validate:"omitempty,oneof=ru en"`
+	Limit int    `query:"limit" validate:"omitempty,gte=1,lte=100"`
+}
+
+// TaskList - страница списка задач и курсор следующей страницы (пустой на последней странице)
+type TaskList struct {
+	Tasks      []repo.Task
+	NextCursor string
 }
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+
+	"simple-service/internal/repo"
+)
+
+// Доменные ошибки сервиса. Транспорт переводит их в свои коды ответа,
+// всё остальное считается внутренней ошибкой
+
+var (
+	// ErrUnauthenticated - вызов без авторизованного пользователя
+	ErrUnauthenticated = errors.New("principal is required")
+	// ErrInvalidCursor - курсор не разбирается или выдан для другой сортировки
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrTaskNotFound - задача не существует или принадлежит другому пользователю
+	ErrTaskNotFound = repo.ErrTaskNotFound
+	// ErrVersionMismatch - задачу изменили после того, как клиент получил её версию
+	ErrVersionMismatch = repo.ErrVersionMismatch
+	// ErrStatusChanged - статус задачи изменился между проверкой перехода и обновлением
+	ErrStatusChanged = repo.ErrStatusChanged
+	// ErrInvalidReminder - напоминание без срока или позже него, возвращается внутри ValidationError
+	ErrInvalidReminder = repo.ErrInvalidReminder
+)
+
+// ValidationError - входные данные не прошли проверку
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// IsDomainError - ошибка вызвана запросом клиента, а не сбоем сервиса
+func IsDomainError(err error) bool {
+	var validationErr *ValidationError
+	var transitionErr *TransitionError
+	return errors.As(err, &validationErr) ||
+		errors.As(err, &transitionErr) ||
+		errors.Is(err, ErrUnauthenticated) ||
+		errors.Is(err, ErrTaskNotFound) ||
+		errors.Is(err, ErrVersionMismatch) ||
+		errors.Is(err, ErrStatusChanged)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"simple-service/internal/repo"
+)
+
+// Параметры выборки списка задач
+
+const (
+	defaultListLimit      = 20
+	defaultListSort       = string(repo.SortCreatedAt)
+	defaultSearchLanguage = repo.LanguageRussian
+)
+
+// taskFilter - фильтр репозитория из провалидированных параметров запроса
+func taskFilter(req ListTasksRequest) (repo.TaskFilter, error) {
+	filter := repo.TaskFilter{Limit: req.Limit, Overdue: req.Overdue}
+	if filter.Limit == 0 {
+		filter.Limit = defaultListLimit
+	}
+
+	sort := req.Sort
+	if sort == "" {
+		sort = defaultListSort
+	}
+	// Префикс "-" означает сортировку по убыванию
+	filter.Sort = repo.TaskSort(strings.TrimPrefix(sort, "-"))
+	filter.Desc = strings.HasPrefix(sort, "-")
+
+	if req.Status != "" {
+		status := repo.TaskStatus(req.Status)
+		filter.Status = &status
+	}
+	// Формат дат уже проверен валидатором. created_at хранится в UTC без зоны
+	// (зона сессии задаётся в repo.NewRepository), а pgx отбрасывает зону
+	// при записи TIMESTAMP, поэтому границы приводятся к UTC
+	if req.CreatedFrom != "" {
+		from, _ := time.Parse(time.RFC3339, req.CreatedFrom)
+		from = from.UTC()
+		filter.CreatedFrom = &from
+	}
+	if req.CreatedTo != "" {
+		to, _ := time.Parse(time.RFC3339, req.CreatedTo)
+		to = to.UTC()
+		filter.CreatedTo = &to
+	}
+
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor, sort)
+		if err != nil {
+			return repo.TaskFilter{}, err
+		}
+		filter.After = after
+	}
+
+	return filter, nil
+}
+
+// nextCursor - курсор следующей страницы или пустая строка на последней странице
+func nextCursor(req ListTasksRequest, page *repo.TaskPage) string {
+	if page.Next == nil {
+		return ""
+	}
+	sort := req.Sort
+	if sort == "" {
+		sort = defaultListSort
+	}
+	return encodeCursor(sort, *page.Next)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"simple-service/internal/repo"
+)
+
+func TestTaskFilter(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	filter, err := taskFilter(ListTasksRequest{
+		Sort:        "-updated_at",
+		CreatedFrom: from.Format(time.RFC3339),
+		CreatedTo:   to.Format(time.RFC3339),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, repo.TaskFilter{
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		Sort:        repo.SortUpdatedAt,
+		Desc:        true,
+		Limit:       defaultListLimit,
+	}, filter)
+}
+
+func TestTaskFilterTimeZone(t *testing.T) {
+	filter, err := taskFilter(ListTasksRequest{
+		CreatedFrom: "2025-01-01T00:00:00+03:00",
+		CreatedTo:   "2025-01-02T00:00:00+03:00",
+	})
+	assert.NoError(t, err)
+
+	// created_at хранится в UTC, граница с зоной +03:00 сдвигается на три часа назад
+	assert.Equal(t, time.Date(2024, 12, 31, 21, 0, 0, 0, time.UTC), *filter.CreatedFrom)
+	assert.Equal(t, time.Date(2025, 1, 1, 21, 0, 0, 0, time.UTC), *filter.CreatedTo)
+	assert.Equal(t, time.UTC, filter.CreatedFrom.Location())
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	position := repo.TaskCursor{ID: 10, Time: time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)}
+
+	raw := encodeCursor("-created_at", position)
+
+	decoded, err := decodeCursor(raw, "-created_at")
+	assert.NoError(t, err)
+	assert.Equal(t, position, *decoded)
+
+	_, err = decodeCursor(raw, "created_at")
+	assert.ErrorIs(t, err, errCursorMismatch)
+
+	_, err = decodeCursor("not a cursor", "created_at")
+	assert.Error(t, err)
+}
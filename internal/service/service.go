@@ -1,87 +1,274 @@
 package service
 
 import (
-	"encoding/json"
-	"github.com/gofiber/fiber/v2"
-	"go.uber.org/zap"
-	"simple-service/internal/dto"
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"simple-service/internal/auth"
+	"simple-service/internal/metrics"
 	"simple-service/internal/repo"
+	"simple-service/internal/tracing"
 	"simple-service/pkg/validator"
-	"strconv"
 )
 
-// Слой бизнес-логики. Тут должна быть основная логика сервиса
+// Слой бизнес-логики. Сервис не зависит от транспорта: принимает обычные Go-типы
+// и возвращает доменные ошибки, которые HTTP, gRPC или CLI переводят в свои ответы
 
-// Service - интерфейс для бизнес-логики
-type Service interface {
-	GetTask(ctx *fiber.Ctx) error
-	CreateTask(ctx *fiber.Ctx) error
+// TaskService - бизнес-логика работы с задачами пользователя
+type TaskService interface {
+	CreateTask(ctx context.Context, principal auth.Principal, req TaskRequest) (int, error)
+	GetTask(ctx context.Context, principal auth.Principal, taskID int) (*repo.Task, error)
+	ListTasks(ctx context.Context, principal auth.Principal, req ListTasksRequest) (*TaskList, error)
+	SearchTasks(ctx context.Context, principal auth.Principal, req SearchTasksRequest) ([]repo.TaskSearchResult, error)
+	// version - ожидаемая версия задачи, nil отключает проверку. Update и Patch возвращают новую версию
+	UpdateTask(ctx context.Context, principal auth.Principal, taskID int, req TaskRequest, version *int) (int, error)
+	PatchTask(ctx context.Context, principal auth.Principal, taskID int, req TaskPatchRequest, version *int) (int, error)
+	DeleteTask(ctx context.Context, principal auth.Principal, taskID int, version *int) error
+	TransitionTask(ctx context.Context, principal auth.Principal, taskID int, req TransitionRequest) (repo.TaskStatus, error)
 }
 
-type service struct {
+type taskService struct {
 	repo repo.Repository
-	log  *zap.SugaredLogger
 }
 
-// NewService - конструктор сервиса
-func NewService(repo repo.Repository, logger *zap.SugaredLogger) Service {
-	return &service{
-		repo: repo,
-		log:  logger,
+// NewTaskService - конструктор сервиса задач
+func NewTaskService(repository repo.Repository) TaskService {
+	return &taskService{repo: repository}
+}
+
+// startSpan - span вокруг метода сервиса, запросы репозитория становятся его дочерними span
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "service."+name)
+}
+
+// endSpan - завершение span, ошибкой отмечаются только сбои, а не ошибки клиента
+func endSpan(span trace.Span, err error) {
+	if err != nil && !IsDomainError(err) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 	}
+	span.End()
 }
 
-// CreateTask - обработчик запроса на создание задачи
-func (s *service) CreateTask(ctx *fiber.Ctx) error {
-	var req TaskRequest
+// validate - проверка входных данных по тегам validate
+func validate(ctx context.Context, req any) error {
+	if err := validator.Validate(ctx, req); err != nil {
+		return &ValidationError{Err: err}
+	}
+	return nil
+}
 
-	// Десериализация JSON-запроса
-	if err := json.Unmarshal(ctx.Body(), &req); err != nil {
-		s.log.Error("Invalid request body", zap.Error(err))
-		return dto.BadResponseError(ctx, dto.FieldBadFormat, "Invalid request body")
+// authenticated - проверка, что вызов выполняется от имени пользователя
+func authenticated(principal auth.Principal) error {
+	if principal.Subject == "" {
+		return ErrUnauthenticated
 	}
+	return nil
+}
 
-	// Валидация входных данных
-	if vErr := validator.Validate(ctx.Context(), req); vErr != nil {
-		return dto.BadResponseError(ctx, dto.FieldIncorrect, vErr.Error())
+// reminderError - нарушение правил напоминания в БД считается ошибкой входных данных
+func reminderError(err error) error {
+	if errors.Is(err, repo.ErrInvalidReminder) {
+		return &ValidationError{Err: repo.ErrInvalidReminder}
 	}
+	return err
+}
 
-	// Вставка задачи в БД через репозиторий
-	task := repo.Task{
+// CreateTask - создание задачи, возвращает её id
+func (s *taskService) CreateTask(ctx context.Context, principal auth.Principal, req TaskRequest) (_ int, err error) {
+	ctx, span := startSpan(ctx, "CreateTask")
+	defer func() { endSpan(span, err) }()
+
+	if err := authenticated(principal); err != nil {
+		return 0, err
+	}
+	if err := validate(ctx, req); err != nil {
+		return 0, err
+	}
+
+	taskID, err := s.repo.CreateTask(ctx, principal.Subject, repo.Task{
 		Title:       req.Title,
 		Description: req.Description,
+		DueAt:       req.DueAt,
+		RemindAt:    req.RemindAt,
+	})
+	if err != nil {
+		return 0, reminderError(errors.Wrap(err, "failed to insert task"))
+	}
+	metrics.TasksCreated.Inc()
+
+	return taskID, nil
+}
+
+// GetTask - получение задачи по id
+func (s *taskService) GetTask(ctx context.Context, principal auth.Principal, taskID int) (_ *repo.Task, err error) {
+	ctx, span := startSpan(ctx, "GetTask")
+	defer func() { endSpan(span, err) }()
+
+	if err := authenticated(principal); err != nil {
+		return nil, err
 	}
-	taskID, err := s.repo.CreateTask(ctx.Context(), task)
+
+	task, err := s.repo.GetTask(ctx, principal.Subject, taskID)
 	if err != nil {
-		s.log.Error("Failed to insert task", zap.Error(err))
-		return dto.InternalServerError(ctx)
+		return nil, errors.Wrap(err, "failed to get task")
+	}
+	return task, nil
+}
+
+// ListTasks - страница задач с учётом фильтров, сортировки и курсора
+func (s *taskService) ListTasks(ctx context.Context, principal auth.Principal, req ListTasksRequest) (_ *TaskList, err error) {
+	ctx, span := startSpan(ctx, "ListTasks")
+	defer func() { endSpan(span, err) }()
+
+	if err := authenticated(principal); err != nil {
+		return nil, err
+	}
+	if err := validate(ctx, req); err != nil {
+		return nil, err
 	}
 
-	// Формирование ответа
-	response := dto.Response{
-		Status: "success",
-		Data:   map[string]int{"task_id": taskID},
+	filter, err := taskFilter(req)
+	if err != nil {
+		return nil, &ValidationError{Err: ErrInvalidCursor}
 	}
 
-	return ctx.Status(fiber.StatusOK).JSON(response)
+	page, err := s.repo.ListTasks(ctx, principal.Subject, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list tasks")
+	}
+
+	return &TaskList{Tasks: page.Tasks, NextCursor: nextCursor(req, page)}, nil
 }
 
-func (s *service) GetTask(ctx *fiber.Ctx) error {
-	taskID, err := strconv.Atoi(ctx.Params("id"))
+// SearchTasks - полнотекстовый поиск задач, самые релевантные первыми
+func (s *taskService) SearchTasks(ctx context.Context, principal auth.Principal, req SearchTasksRequest) (_ []repo.TaskSearchResult, err error) {
+	ctx, span := startSpan(ctx, "SearchTasks")
+	defer func() { endSpan(span, err) }()
+
+	if err := authenticated(principal); err != nil {
+		return nil, err
+	}
+	if err := validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	lang := repo.SearchLanguage(req.Lang)
+	if lang == "" {
+		lang = defaultSearchLanguage
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	results, err := s.repo.SearchTasks(ctx, principal.Subject, req.Query, lang, limit)
 	if err != nil {
-		s.log.Error("Failed to parse int", zap.Error(err))
-		return dto.BadResponseError(ctx, dto.FieldBadFormat, "ID must be only number")
+		return nil, errors.Wrap(err, "failed to search tasks")
 	}
-	task, err := s.repo.GetTask(ctx.Context(), taskID)
+	return results, nil
+}
+
+// UpdateTask - полное обновление задачи
+func (s *taskService) UpdateTask(ctx context.Context, principal auth.Principal, taskID int, req TaskRequest, version *int) (_ int, err error) {
+	ctx, span := startSpan(ctx, "UpdateTask")
+	defer func() { endSpan(span, err) }()
+
+	if err := authenticated(principal); err != nil {
+		return 0, err
+	}
+	if err := validate(ctx, req); err != nil {
+		return 0, err
+	}
+
+	newVersion, err := s.repo.UpdateTask(ctx, principal.Subject, taskID, repo.Task{
+		Title:       req.Title,
+		Description: req.Description,
+		DueAt:       req.DueAt,
+		RemindAt:    req.RemindAt,
+	}, version)
 	if err != nil {
-		s.log.Error("Failed to get task", zap.Error(err))
-		return dto.InternalServerError(ctx)
+		return 0, reminderError(errors.Wrap(err, "failed to update task"))
+	}
+	return newVersion, nil
+}
+
+// PatchTask - частичное обновление задачи
+func (s *taskService) PatchTask(ctx context.Context, principal auth.Principal, taskID int, req TaskPatchRequest, version *int) (_ int, err error) {
+	ctx, span := startSpan(ctx, "PatchTask")
+	defer func() { endSpan(span, err) }()
+
+	if err := authenticated(principal); err != nil {
+		return 0, err
+	}
+	if err := validate(ctx, req); err != nil {
+		return 0, err
+	}
+
+	// Напоминание сравнивается со сроком, уже сохранённым в задаче, поэтому его проверяет ограничение БД
+	newVersion, err := s.repo.PatchTask(ctx, principal.Subject, taskID, repo.TaskPatch{
+		Title:       req.Title,
+		Description: req.Description,
+		DueAt:       req.DueAt,
+		RemindAt:    req.RemindAt,
+	}, version)
+	if err != nil {
+		return 0, reminderError(errors.Wrap(err, "failed to patch task"))
+	}
+	return newVersion, nil
+}
+
+// DeleteTask - удаление задачи. Администратор может удалить задачу любого пользователя
+func (s *taskService) DeleteTask(ctx context.Context, principal auth.Principal, taskID int, version *int) (err error) {
+	ctx, span := startSpan(ctx, "DeleteTask")
+	defer func() { endSpan(span, err) }()
+
+	if err := authenticated(principal); err != nil {
+		return err
+	}
+
+	if principal.Can(auth.PermDeleteAny) {
+		err = s.repo.DeleteAnyTask(ctx, taskID, version)
+	} else {
+		err = s.repo.DeleteTask(ctx, principal.Subject, taskID, version)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to delete task")
+	}
+	return nil
+}
+
+// TransitionTask - смена статуса задачи по правилам переходов, возвращает новый статус
+func (s *taskService) TransitionTask(ctx context.Context, principal auth.Principal, taskID int, req TransitionRequest) (_ repo.TaskStatus, err error) {
+	ctx, span := startSpan(ctx, "TransitionTask")
+	defer func() { endSpan(span, err) }()
+
+	if err := authenticated(principal); err != nil {
+		return "", err
+	}
+	if err := validate(ctx, req); err != nil {
+		return "", err
+	}
+
+	task, err := s.repo.GetTask(ctx, principal.Subject, taskID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get task")
+	}
+
+	to := repo.TaskStatus(req.Status)
+	if err := checkTransition(task.Status, to, req.Reopen); err != nil {
+		return "", err
+	}
+
+	if err := s.repo.UpdateTaskStatus(ctx, principal.Subject, taskID, task.Status, to); err != nil {
+		return "", errors.Wrap(err, "failed to update task status")
 	}
-	// Формирование ответа
-	response := dto.Response{
-		Status: "success",
-		Data:   task,
+	if to == repo.StatusDone {
+		metrics.TasksCompleted.Inc()
 	}
 
-	return ctx.Status(fiber.StatusOK).JSON(response)
+	return to, nil
 }
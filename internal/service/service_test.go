@@ -1,107 +1,332 @@
 package service
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
-	"net/http"
 	"testing"
+	"time"
 
-	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"go.uber.org/zap"
-	"simple-service/internal/dto"
+	"github.com/stretchr/testify/require"
+
+	"simple-service/internal/auth"
 	"simple-service/internal/repo"
 	"simple-service/internal/repo/mocks"
 )
 
+const testOwner = "user"
+
+var (
+	editor = auth.Principal{Subject: testOwner, Roles: []string{auth.RoleEditor}}
+	admin  = auth.Principal{Subject: "root", Roles: []string{auth.RoleAdmin}}
+)
+
+// intPtr - указатель на значение для ожидаемой версии задачи
+func intPtr(v int) *int {
+	return &v
+}
+
+// timePtr - указатель на время для срока и напоминания задачи
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
 // TestCreateTask - тестирование метода CreateTask
 func TestCreateTask(t *testing.T) {
-	// Создаем мок репозитория
-	mockRepo := new(mocks.Repository)
-	logger := zap.NewNop().Sugar() // Без вывода логов
+	ctx := context.Background()
 
-	// Создаем экземпляр сервиса с мок-репозиторием
-	s := NewService(mockRepo, logger)
+	t.Run("успешное создание задачи", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		s := NewTaskService(mockRepo)
 
-	// Инициализируем Fiber-контекст
-	app := fiber.New()
-	app.Post("/tasks", s.CreateTask)
+		mockRepo.On("CreateTask", mock.Anything, testOwner, repo.Task{Title: "Task", Description: "Description"}).
+			Return(1, nil).Once()
 
-	t.Run("успешное создание задачи", func(t *testing.T) {
-		task := TaskRequest{
-			Title:       "Test Task",
-			Description: "Test Description",
-		}
-		body, _ := json.Marshal(task)
-
-		// Ожидаем, что вызов метода `CreateTask` в репозитории вернёт ID = 1
-		mockRepo.On("CreateTask", mock.Anything, repo.Task{
-			Title:       task.Title,
-			Description: task.Description,
-		}).Return(1, nil).Once()
-
-		// Отправляем запрос
-		req, err := http.NewRequest("POST", "/tasks", bytes.NewReader(body))
+		taskID, err := s.CreateTask(ctx, editor, TaskRequest{Title: "Task", Description: "Description"})
 		assert.NoError(t, err)
-		req.Header.Set("Content-Type", "application/json")
+		assert.Equal(t, 1, taskID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ошибка валидации входных данных", func(t *testing.T) {
+		s := NewTaskService(new(mocks.Repository))
+
+		_, err := s.CreateTask(ctx, editor, TaskRequest{})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.True(t, IsDomainError(err))
+	})
+
+	t.Run("срок и напоминание", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		s := NewTaskService(mockRepo)
+		due := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+		remind := due.Add(-time.Hour)
 
-		// Выполняем запрос
-		resp, err := app.Test(req)
+		mockRepo.On("CreateTask", mock.Anything, testOwner, repo.Task{Title: "Task", DueAt: &due, RemindAt: &remind}).
+			Return(1, nil).Once()
+
+		_, err := s.CreateTask(ctx, editor, TaskRequest{Title: "Task", DueAt: &due, RemindAt: &remind})
 		assert.NoError(t, err)
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("напоминание позже срока", func(t *testing.T) {
+		s := NewTaskService(new(mocks.Repository))
+		due := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+		remind := due.Add(time.Hour)
+
+		_, err := s.CreateTask(ctx, editor, TaskRequest{Title: "Task", DueAt: &due, RemindAt: &remind})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("напоминание без срока", func(t *testing.T) {
+		s := NewTaskService(new(mocks.Repository))
+		remind := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+
+		_, err := s.CreateTask(ctx, editor, TaskRequest{Title: "Task", RemindAt: &remind})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("вызов без пользователя", func(t *testing.T) {
+		s := NewTaskService(new(mocks.Repository))
 
-		// Проверяем ответ
-		var response dto.Response
-		json.NewDecoder(resp.Body).Decode(&response)
-		assert.Equal(t, "success", response.Status)
+		_, err := s.CreateTask(ctx, auth.Principal{}, TaskRequest{Title: "Task"})
+		assert.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("ошибка при создании задачи в БД", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		s := NewTaskService(mockRepo)
+
+		mockRepo.On("CreateTask", mock.Anything, testOwner, repo.Task{Title: "Task"}).Return(0, errors.New("DB error")).Once()
 
-		// Проверяем вызов мок-методов
+		_, err := s.CreateTask(ctx, editor, TaskRequest{Title: "Task"})
+		assert.Error(t, err)
+		assert.False(t, IsDomainError(err))
 		mockRepo.AssertExpectations(t)
 	})
+}
 
-	t.Run("ошибка валидации входных данных", func(t *testing.T) {
-		body := []byte(`{}`) // Пустое тело, `title` обязателен
+// TestGetTask - тестирование метода GetTask
+func TestGetTask(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.Repository)
+	s := NewTaskService(mockRepo)
+
+	t.Run("успешное получение задачи", func(t *testing.T) {
+		task := &repo.Task{ID: 1, Title: "Task", Version: 2}
+		mockRepo.On("GetTask", mock.Anything, testOwner, 1).Return(task, nil).Once()
+
+		got, err := s.GetTask(ctx, editor, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, task, got)
+	})
+
+	t.Run("задача не найдена", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 2).Return(nil, repo.ErrTaskNotFound).Once()
+
+		_, err := s.GetTask(ctx, editor, 2)
+		assert.ErrorIs(t, err, ErrTaskNotFound)
+	})
 
-		req, err := http.NewRequest("POST", "/tasks", bytes.NewReader(body))
+	mockRepo.AssertExpectations(t)
+}
+
+// TestListTasks - тестирование метода ListTasks
+func TestListTasks(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.Repository)
+	s := NewTaskService(mockRepo)
+
+	t.Run("параметры по умолчанию", func(t *testing.T) {
+		mockRepo.On("ListTasks", mock.Anything, testOwner, repo.TaskFilter{Sort: repo.SortCreatedAt, Limit: defaultListLimit}).
+			Return(&repo.TaskPage{Tasks: []repo.Task{{ID: 1}}}, nil).Once()
+
+		list, err := s.ListTasks(ctx, editor, ListTasksRequest{})
 		assert.NoError(t, err)
-		req.Header.Set("Content-Type", "application/json")
+		assert.Len(t, list.Tasks, 1)
+		assert.Empty(t, list.NextCursor)
+	})
+
+	t.Run("курсор следующей страницы", func(t *testing.T) {
+		next := repo.TaskCursor{ID: 2, Title: "b"}
+		mockRepo.On("ListTasks", mock.Anything, testOwner, repo.TaskFilter{Sort: repo.SortTitle, Desc: true, Limit: 2}).
+			Return(&repo.TaskPage{Tasks: []repo.Task{{ID: 1}, {ID: 2}}, Next: &next}, nil).Once()
 
-		resp, err := app.Test(req)
+		list, err := s.ListTasks(ctx, editor, ListTasksRequest{Limit: 2, Sort: "-title"})
+		require.NoError(t, err)
+		require.NotEmpty(t, list.NextCursor)
+
+		mockRepo.On("ListTasks", mock.Anything, testOwner, repo.TaskFilter{Sort: repo.SortTitle, Desc: true, After: &next, Limit: 2}).
+			Return(&repo.TaskPage{}, nil).Once()
+
+		_, err = s.ListTasks(ctx, editor, ListTasksRequest{Limit: 2, Sort: "-title", Cursor: list.NextCursor})
 		assert.NoError(t, err)
-		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("курсор от другой сортировки", func(t *testing.T) {
+		cursor := encodeCursor("title", repo.TaskCursor{ID: 2, Title: "b"})
 
-		var response dto.Response
-		json.NewDecoder(resp.Body).Decode(&response)
-		assert.Equal(t, "error", response.Status)
+		_, err := s.ListTasks(ctx, editor, ListTasksRequest{Sort: "-title", Cursor: cursor})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
 	})
 
-	t.Run("ошибка при создании задачи в БД", func(t *testing.T) {
-		task := TaskRequest{
-			Title:       "Test Task",
-			Description: "Test Description",
-		}
-		body, _ := json.Marshal(task)
-
-		// Ожидаем ошибку при вставке в БД
-		mockRepo.On("CreateTask", mock.Anything, repo.Task{
-			Title:       task.Title,
-			Description: task.Description,
-		}).Return(0, errors.New("DB error")).Once()
-
-		req, err := http.NewRequest("POST", "/tasks", bytes.NewReader(body))
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := app.Test(req)
+	t.Run("некорректные параметры", func(t *testing.T) {
+		_, err := s.ListTasks(ctx, editor, ListTasksRequest{Limit: 101})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestSearchTasks - тестирование метода SearchTasks
+func TestSearchTasks(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.Repository)
+	s := NewTaskService(mockRepo)
+
+	t.Run("язык и лимит по умолчанию", func(t *testing.T) {
+		mockRepo.On("SearchTasks", mock.Anything, testOwner, "отчёт", repo.LanguageRussian, defaultListLimit).
+			Return([]repo.TaskSearchResult{{Task: repo.Task{ID: 1}}}, nil).Once()
+
+		results, err := s.SearchTasks(ctx, editor, SearchTasksRequest{Query: "отчёт"})
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+	})
+
+	t.Run("пустой запрос", func(t *testing.T) {
+		_, err := s.SearchTasks(ctx, editor, SearchTasksRequest{})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestUpdateTask - тестирование методов UpdateTask и PatchTask
+func TestUpdateTask(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.Repository)
+	s := NewTaskService(mockRepo)
+
+	t.Run("полное обновление с проверкой версии", func(t *testing.T) {
+		mockRepo.On("UpdateTask", mock.Anything, testOwner, 1, repo.Task{Title: "Task"}, intPtr(3)).Return(4, nil).Once()
+
+		version, err := s.UpdateTask(ctx, editor, 1, TaskRequest{Title: "Task"}, intPtr(3))
 		assert.NoError(t, err)
+		assert.Equal(t, 4, version)
+	})
+
+	t.Run("версия задачи изменилась", func(t *testing.T) {
+		mockRepo.On("UpdateTask", mock.Anything, testOwner, 1, repo.Task{Title: "Task"}, intPtr(1)).
+			Return(0, repo.ErrVersionMismatch).Once()
+
+		_, err := s.UpdateTask(ctx, editor, 1, TaskRequest{Title: "Task"}, intPtr(1))
+		assert.ErrorIs(t, err, ErrVersionMismatch)
+	})
+
+	t.Run("частичное обновление", func(t *testing.T) {
+		title := "Patched"
+		mockRepo.On("PatchTask", mock.Anything, testOwner, 1, repo.TaskPatch{Title: &title}, (*int)(nil)).Return(2, nil).Once()
+
+		version, err := s.PatchTask(ctx, editor, 1, TaskPatchRequest{Title: &title}, nil)
 		assert.NoError(t, err)
-		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, 2, version)
+	})
 
-		var response dto.Response
-		json.NewDecoder(resp.Body).Decode(&response)
-		assert.Equal(t, "error", response.Status)
+	t.Run("напоминание позже сохранённого срока", func(t *testing.T) {
+		remind := repo.NullableTime{Time: timePtr(time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)), Set: true}
+		mockRepo.On("PatchTask", mock.Anything, testOwner, 1, repo.TaskPatch{RemindAt: remind}, (*int)(nil)).
+			Return(0, repo.ErrInvalidReminder).Once()
 
-		mockRepo.AssertExpectations(t)
+		_, err := s.PatchTask(ctx, editor, 1, TaskPatchRequest{RemindAt: remind}, nil)
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.ErrorIs(t, err, ErrInvalidReminder)
+	})
+
+	t.Run("пустой заголовок", func(t *testing.T) {
+		title := ""
+		_, err := s.PatchTask(ctx, editor, 1, TaskPatchRequest{Title: &title}, nil)
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
 	})
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestDeleteTask - тестирование метода DeleteTask
+func TestDeleteTask(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.Repository)
+	s := NewTaskService(mockRepo)
+
+	t.Run("удаление своей задачи", func(t *testing.T) {
+		mockRepo.On("DeleteTask", mock.Anything, testOwner, 1, intPtr(1)).Return(nil).Once()
+
+		assert.NoError(t, s.DeleteTask(ctx, editor, 1, intPtr(1)))
+	})
+
+	t.Run("администратор удаляет чужую задачу", func(t *testing.T) {
+		mockRepo.On("DeleteAnyTask", mock.Anything, 2, (*int)(nil)).Return(nil).Once()
+
+		assert.NoError(t, s.DeleteTask(ctx, admin, 2, nil))
+	})
+
+	t.Run("задача не найдена", func(t *testing.T) {
+		mockRepo.On("DeleteTask", mock.Anything, testOwner, 3, (*int)(nil)).Return(repo.ErrTaskNotFound).Once()
+
+		assert.ErrorIs(t, s.DeleteTask(ctx, editor, 3, nil), ErrTaskNotFound)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestTransitionTask - тестирование метода TransitionTask
+func TestTransitionTask(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.Repository)
+	s := NewTaskService(mockRepo)
+
+	t.Run("успешная смена статуса", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 1).Return(&repo.Task{Status: repo.StatusNew}, nil).Once()
+		mockRepo.On("UpdateTaskStatus", mock.Anything, testOwner, 1, repo.StatusNew, repo.StatusDone).Return(nil).Once()
+
+		status, err := s.TransitionTask(ctx, editor, 1, TransitionRequest{Status: "done"})
+		assert.NoError(t, err)
+		assert.Equal(t, repo.StatusDone, status)
+	})
+
+	t.Run("недопустимый переход", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 2).Return(&repo.Task{Status: repo.StatusDone}, nil).Once()
+
+		_, err := s.TransitionTask(ctx, editor, 2, TransitionRequest{Status: "new"})
+		var transitionErr *TransitionError
+		assert.ErrorAs(t, err, &transitionErr)
+		assert.True(t, IsDomainError(err))
+	})
+
+	t.Run("статус изменён параллельно", func(t *testing.T) {
+		mockRepo.On("GetTask", mock.Anything, testOwner, 3).Return(&repo.Task{Status: repo.StatusNew}, nil).Once()
+		mockRepo.On("UpdateTaskStatus", mock.Anything, testOwner, 3, repo.StatusNew, repo.StatusInProgress).
+			Return(repo.ErrStatusChanged).Once()
+
+		_, err := s.TransitionTask(ctx, editor, 3, TransitionRequest{Status: "in_progress"})
+		assert.ErrorIs(t, err, ErrStatusChanged)
+	})
+
+	t.Run("неизвестный статус", func(t *testing.T) {
+		_, err := s.TransitionTask(ctx, editor, 1, TransitionRequest{Status: "archived"})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	mockRepo.AssertExpectations(t)
 }
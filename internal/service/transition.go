@@ -0,0 +1,40 @@
+package service
+
+import (
+	"fmt"
+
+	"simple-service/internal/repo"
+)
+
+// Правила смены статуса задачи
+
+// transitions - допустимые переходы между статусами.
+// Из done можно выйти только через явное переоткрытие (reopen)
+var transitions = map[repo.TaskStatus][]repo.TaskStatus{
+	repo.StatusNew:        {repo.StatusInProgress, repo.StatusDone},
+	repo.StatusInProgress: {repo.StatusNew, repo.StatusDone},
+	repo.StatusDone:       {},
+}
+
+// TransitionError - ошибка недопустимого перехода статуса
+type TransitionError struct {
+	From repo.TaskStatus
+	To   repo.TaskStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("transition from %q to %q is not allowed", e.From, e.To)
+}
+
+// checkTransition - проверка, что задачу можно перевести из from в to
+func checkTransition(from, to repo.TaskStatus, reopen bool) error {
+	if from == repo.StatusDone && to != repo.StatusDone && reopen {
+		return nil
+	}
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return &TransitionError{From: from, To: to}
+}
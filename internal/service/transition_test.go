@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"simple-service/internal/repo"
+)
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    repo.TaskStatus
+		to      repo.TaskStatus
+		reopen  bool
+		wantErr bool
+	}{
+		{name: "new -> in_progress", from: repo.StatusNew, to: repo.StatusInProgress},
+		{name: "new -> done", from: repo.StatusNew, to: repo.StatusDone},
+		{name: "in_progress -> new", from: repo.StatusInProgress, to: repo.StatusNew},
+		{name: "in_progress -> done", from: repo.StatusInProgress, to: repo.StatusDone},
+		{name: "new -> new", from: repo.StatusNew, to: repo.StatusNew, wantErr: true},
+		{name: "done -> new без reopen", from: repo.StatusDone, to: repo.StatusNew, wantErr: true},
+		{name: "done -> in_progress без reopen", from: repo.StatusDone, to: repo.StatusInProgress, wantErr: true},
+		{name: "done -> new с reopen", from: repo.StatusDone, to: repo.StatusNew, reopen: true},
+		{name: "done -> done с reopen", from: repo.StatusDone, to: repo.StatusDone, reopen: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkTransition(tt.from, tt.to, tt.reopen)
+			if tt.wantErr {
+				var tErr *TransitionError
+				assert.ErrorAs(t, err, &tErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+
+	"simple-service/internal/config"
+)
+
+func TestSetup(t *testing.T) {
+	t.Run("Без экспорта провайдер не создаётся", func(t *testing.T) {
+		shutdown, err := Setup(context.Background(), config.Tracing{Exporter: ExporterNone})
+		assert.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Неизвестный экспорт", func(t *testing.T) {
+		_, err := Setup(context.Background(), config.Tracing{Exporter: "zipkin"})
+		assert.Error(t, err)
+	})
+}
+
+func TestLogFields(t *testing.T) {
+	t.Run("Без span полей нет", func(t *testing.T) {
+		assert.Nil(t, LogFields(context.Background()))
+	})
+
+	t.Run("Идентификаторы текущего span", func(t *testing.T) {
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  spanID,
+		}))
+
+		assert.Equal(t, []any{
+			"trace_id", "4bf92f3577b34da6a3ce929d0e0e4736",
+			"span_id", "00f067aa0ba902b7",
+		}, LogFields(ctx))
+	})
+}
@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// SQL-файлы миграций встраиваются в бинарник, чтобы сервис мог применить их сам
+
+//go:embed postgres/*.sql
+var files embed.FS
+
+// Postgres - файлы миграций PostgreSQL вида 000001_name.up.sql / 000001_name.down.sql
+func Postgres() fs.FS {
+	sub, _ := fs.Sub(files, "postgres")
+	return sub
+}
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS tasks_set_updated_at ON tasks;
+DROP FUNCTION IF EXISTS set_updated_at();
@@ -0,0 +1,14 @@
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,              -- Уникальный идентификатор пользователя
+    name TEXT NOT NULL UNIQUE,          -- Имя пользователя (subject из авторизации)
+    created_at TIMESTAMP DEFAULT now()  -- Время создания пользователя
+);
+
+-- Существующие задачи передаются пользователю default
+INSERT INTO users (name) VALUES ('default');
+
+ALTER TABLE tasks ADD COLUMN owner_id INTEGER REFERENCES users (id) ON DELETE CASCADE;
+UPDATE tasks SET owner_id = (SELECT id FROM users WHERE name = 'default');
+ALTER TABLE tasks ALTER COLUMN owner_id SET NOT NULL;
+
+CREATE INDEX tasks_owner_id_idx ON tasks (owner_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tasks_owner_title_idx;
+DROP INDEX IF EXISTS tasks_owner_updated_at_idx;
+DROP INDEX IF EXISTS tasks_owner_created_at_idx;
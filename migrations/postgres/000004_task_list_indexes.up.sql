@@ -0,0 +1,4 @@
+-- Индексы для keyset-пагинации списка задач владельца
+CREATE INDEX tasks_owner_created_at_idx ON tasks (owner_id, created_at, id);
+CREATE INDEX tasks_owner_updated_at_idx ON tasks (owner_id, updated_at, id);
+CREATE INDEX tasks_owner_title_idx ON tasks (owner_id, title, id);
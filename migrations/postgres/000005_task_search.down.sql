@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tasks_search_en_idx;
+DROP INDEX IF EXISTS tasks_search_ru_idx;
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_en, DROP COLUMN IF EXISTS search_ru;
@@ -0,0 +1,14 @@
+-- Полнотекстовый поиск по заголовку и описанию задачи.
+-- Для каждого языка свой столбец со стеммингом, заголовок весит больше описания
+ALTER TABLE tasks
+    ADD COLUMN search_ru tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(description, '')), 'B')
+    ) STORED,
+    ADD COLUMN search_en tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX tasks_search_ru_idx ON tasks USING GIN (search_ru);
+CREATE INDEX tasks_search_en_idx ON tasks USING GIN (search_en);
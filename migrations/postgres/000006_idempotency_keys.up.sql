@@ -0,0 +1,12 @@
+CREATE TABLE idempotency_keys (
+    owner_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE, -- Владелец ключа, ключи разных пользователей не пересекаются
+    key TEXT NOT NULL,                                                 -- Значение заголовка Idempotency-Key
+    request_hash TEXT NOT NULL,                                        -- SHA-256 метода, пути и тела первого запроса
+    status_code INTEGER,                                               -- Статус сохранённого ответа, NULL пока запрос выполняется
+    response BYTEA,                                                    -- Тело сохранённого ответа
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL,                                     -- После этого времени ключ можно использовать заново
+    PRIMARY KEY (owner_id, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
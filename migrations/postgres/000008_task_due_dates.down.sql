@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS tasks_owner_due_at_idx;
+DROP INDEX IF EXISTS tasks_pending_reminders_idx;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_remind_before_due;
+ALTER TABLE tasks DROP COLUMN IF EXISTS reminder_claimed_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS reminded_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS remind_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS due_at;
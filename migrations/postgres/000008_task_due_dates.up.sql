@@ -0,0 +1,18 @@
+-- Срок выполнения и напоминание. TIMESTAMPTZ, чтобы сроки с часовым поясом клиента
+-- сравнивались с now() без сдвига
+ALTER TABLE tasks ADD COLUMN due_at TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN remind_at TIMESTAMPTZ;
+-- Время отправки напоминания; NULL - ещё не отправлено
+ALTER TABLE tasks ADD COLUMN reminded_at TIMESTAMPTZ;
+-- Время, когда реплика забрала напоминание на отправку. Если она не отметила его
+-- отправленным за время аренды, напоминание забирает другая реплика
+ALTER TABLE tasks ADD COLUMN reminder_claimed_at TIMESTAMPTZ;
+
+-- Напоминание задаётся только вместе со сроком и не позже него
+ALTER TABLE tasks ADD CONSTRAINT tasks_remind_before_due
+    CHECK (remind_at IS NULL OR (due_at IS NOT NULL AND remind_at <= due_at));
+
+-- Поиск неотправленных напоминаний планировщиком
+CREATE INDEX tasks_pending_reminders_idx ON tasks (remind_at) WHERE reminded_at IS NULL AND remind_at IS NOT NULL;
+-- Фильтр просроченных задач владельца
+CREATE INDEX tasks_owner_due_at_idx ON tasks (owner_id, due_at) WHERE due_at IS NOT NULL;
@@ -0,0 +1,256 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Go-клиент REST API задач. Повторяет запросы при 429 и, для безопасных запросов, при 5xx, сетевых ошибках
+// и незавершённом запросе с тем же ключом идемпотентности
+
+const (
+	defaultTimeout    = 10 * time.Second
+	defaultMaxRetries = 3
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 2 * time.Second
+
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// Config - настройки клиента, нулевые значения заменяются значениями по умолчанию
+type Config struct {
+	BaseURL    string        // Адрес сервиса, например http://localhost:8080
+	Token      string        // Bearer-токен пользователя
+	Timeout    time.Duration // Таймаут одной попытки запроса, по умолчанию 10s
+	MaxRetries int           // Число повторов после первой попытки, по умолчанию 3; отрицательное значение отключает повторы
+	MinBackoff time.Duration // Пауза перед первым повтором, по умолчанию 100ms, дальше удваивается
+	MaxBackoff time.Duration // Максимальная пауза между повторами, по умолчанию 2s
+	HTTPClient *http.Client  // HTTP-клиент; если задан, Timeout не используется
+	UserAgent  string
+}
+
+// Client - клиент REST API задач, безопасен для использования из нескольких горутин
+type Client struct {
+	baseURL    *url.URL
+	token      string
+	http       *http.Client
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	userAgent  string
+}
+
+// New - конструктор клиента
+func New(cfg Config) (*Client, error) {
+	baseURL, err := url.Parse(strings.TrimRight(cfg.BaseURL, "/"))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid base URL")
+	}
+	if baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, errors.Errorf("invalid base URL %q", cfg.BaseURL)
+	}
+
+	c := &Client{
+		baseURL:    baseURL,
+		token:      cfg.Token,
+		http:       cfg.HTTPClient,
+		maxRetries: cfg.MaxRetries,
+		minBackoff: cfg.MinBackoff,
+		maxBackoff: cfg.MaxBackoff,
+		userAgent:  cfg.UserAgent,
+	}
+	if c.http == nil {
+		timeout := cfg.Timeout
+		if timeout == 0 {
+			timeout = defaultTimeout
+		}
+		c.http = &http.Client{Timeout: timeout}
+	}
+	switch {
+	case c.maxRetries == 0:
+		c.maxRetries = defaultMaxRetries
+	case c.maxRetries < 0:
+		c.maxRetries = 0
+	}
+	if c.minBackoff == 0 {
+		c.minBackoff = defaultMinBackoff
+	}
+	if c.maxBackoff == 0 {
+		c.maxBackoff = defaultMaxBackoff
+	}
+	if c.userAgent == "" {
+		c.userAgent = "simple-service-go-client"
+	}
+	return c, nil
+}
+
+// request - запрос к API
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   any
+	// safe - повтор запроса после ответа 5xx или сетевой ошибки не изменит результат
+	safe bool
+}
+
+// envelope - общий формат ответа сервиса
+type envelope struct {
+	Status     string          `json:"status"`
+	Error      *apiErrorBody   `json:"error"`
+	Data       json.RawMessage `json:"data"`
+	NextCursor string          `json:"next_cursor"`
+}
+
+type apiErrorBody struct {
+	Code string `json:"code"`
+	Desc string `json:"desc"`
+}
+
+// response - успешный ответ: заголовки и курсор следующей страницы
+type response struct {
+	header     http.Header
+	nextCursor string
+}
+
+// do - выполнение запроса с повторами; data ответа декодируется в out
+func (c *Client) do(ctx context.Context, req request, out any) (*response, error) {
+	var payload []byte
+	if req.body != nil {
+		var err error
+		if payload, err = json.Marshal(req.body); err != nil {
+			return nil, errors.Wrap(err, "failed to encode request")
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req, payload, out)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt >= c.maxRetries || !retryable(ctx, req, err) {
+			return nil, err
+		}
+
+		timer := time.NewTimer(c.backoff(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// send - одна попытка запроса
+func (c *Client) send(ctx context.Context, req request, payload []byte, out any) (*response, error) {
+	target := c.baseURL.JoinPath(req.path)
+	target.RawQuery = req.query.Encode()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target.String(), body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+	for key, values := range req.header {
+		httpReq.Header[key] = values
+	}
+	if payload != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	httpReq.Header.Set("User-Agent", c.userAgent)
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s", req.method, req.path)
+	}
+	defer httpResp.Body.Close()
+
+	var env envelope
+	decodeErr := json.NewDecoder(httpResp.Body).Decode(&env)
+
+	if env.Error != nil || httpResp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{StatusCode: httpResp.StatusCode, RetryAfter: retryAfter(httpResp.Header)}
+		if env.Error != nil {
+			apiErr.Code, apiErr.Desc = env.Error.Code, env.Error.Desc
+		}
+		return nil, apiErr
+	}
+	if decodeErr != nil {
+		return nil, errors.Wrap(decodeErr, "failed to decode response")
+	}
+
+	if out != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return nil, errors.Wrap(err, "failed to decode response data")
+		}
+	}
+	return &response{header: httpResp.Header, nextCursor: env.NextCursor}, nil
+}
+
+// retryable - стоит ли повторить запрос. 429 отклоняется до обработки и повторяется всегда,
+// 5xx и сетевые ошибки - только для безопасных запросов. IDEMPOTENCY_IN_PROGRESS означает,
+// что предыдущая попытка с тем же ключом ещё выполняется: повтор получит её сохранённый ответ
+func retryable(ctx context.Context, req request, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests ||
+			(req.safe && (apiErr.StatusCode >= http.StatusInternalServerError || apiErr.Code == CodeIdempotencyPending))
+	}
+	return req.safe
+}
+
+// backoff - пауза перед повтором attempt+1: Retry-After сервиса или экспоненциальная задержка со случайной добавкой
+func (c *Client) backoff(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+
+	delay := c.minBackoff << attempt
+	if delay <= 0 || delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+	// Половина паузы фиксирована, половина случайна, чтобы клиенты не повторяли запросы одновременно
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// retryAfter - значение заголовка Retry-After в секундах
+func retryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// newIdempotencyKey - случайный ключ идемпотентности из 16 байт в hex
+func newIdempotencyKey() string {
+	var b [16]byte
+	_, _ = cryptorand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
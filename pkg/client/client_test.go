@@ -0,0 +1,284 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient - клиент тестового сервера с короткими паузами между повторами
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := New(Config{BaseURL: srv.URL, Token: "secret", MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+	require.NoError(t, err)
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code, desc string) {
+	writeJSON(w, status, map[string]any{"status": "error", "error": map[string]string{"code": code, "desc": desc}})
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{BaseURL: "localhost:8080"})
+	assert.Error(t, err)
+
+	c, err := New(Config{BaseURL: "http://localhost:8080/"})
+	require.NoError(t, err)
+	assert.Equal(t, defaultMaxRetries, c.maxRetries)
+
+	c, err = New(Config{BaseURL: "http://localhost:8080", MaxRetries: -1})
+	require.NoError(t, err)
+	assert.Zero(t, c.maxRetries)
+}
+
+func TestCreateTask(t *testing.T) {
+	var keys []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/create_task", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var input TaskInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Equal(t, TaskInput{Title: "Task", Description: "Desc"}, input)
+
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			writeError(w, http.StatusInternalServerError, CodeServiceUnavailable, "unavailable")
+			return
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		writeJSON(w, http.StatusOK, map[string]any{"status": "success", "data": map[string]int{"task_id": 5}})
+	})
+
+	created, err := c.CreateTask(context.Background(), TaskInput{Title: "Task", Description: "Desc"}, CreateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, &Created{TaskID: 5, Replayed: true}, created)
+
+	// Повтор после 5xx отправляется с тем же ключом идемпотентности
+	require.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+}
+
+func TestGetAndList(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/tasks/1":
+			writeJSON(w, http.StatusOK, map[string]any{"status": "success", "data": Task{ID: 1, Title: "Task", Status: StatusNew, CreatedAt: created, Version: 2}})
+		case "/v1/tasks":
+			assert.Equal(t, "5", r.URL.Query().Get("limit"))
+			assert.Equal(t, "-title", r.URL.Query().Get("sort"))
+			assert.Equal(t, "done", r.URL.Query().Get("status"))
+			assert.Equal(t, "2025-01-02T03:04:05Z", r.URL.Query().Get("created_from"))
+			assert.Equal(t, "2025-01-02T00:00:00+03:00", r.URL.Query().Get("created_to"))
+			assert.Equal(t, "true", r.URL.Query().Get("overdue"))
+			writeJSON(w, http.StatusOK, map[string]any{"status": "success", "data": []Task{{ID: 1}, {ID: 2}}, "next_cursor": "abc"})
+		default:
+			writeError(w, http.StatusNotFound, CodeTaskNotFound, "Task not found")
+		}
+	})
+	ctx := context.Background()
+
+	t.Run("Получение задачи", func(t *testing.T) {
+		task, err := c.GetTask(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &Task{ID: 1, Title: "Task", Status: StatusNew, CreatedAt: created, Version: 2}, task)
+	})
+
+	t.Run("Задача не найдена", func(t *testing.T) {
+		_, err := c.GetTask(ctx, 2)
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Equal(t, CodeTaskNotFound, apiErr.Code)
+	})
+
+	t.Run("Список с фильтрами", func(t *testing.T) {
+		// Граница отправляется со смещением зоны вызывающего
+		msk := time.FixedZone("MSK", 3*60*60)
+		list, err := c.ListTasks(ctx, ListOptions{
+			Limit:       5,
+			Sort:        "-title",
+			Status:      StatusDone,
+			CreatedFrom: created,
+			CreatedTo:   time.Date(2025, 1, 2, 0, 0, 0, 0, msk),
+			Overdue:     true,
+		})
+		require.NoError(t, err)
+		assert.Len(t, list.Tasks, 2)
+		assert.Equal(t, "abc", list.NextCursor)
+	})
+}
+
+func TestVersionPreconditions(t *testing.T) {
+	var ifMatch []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		ifMatch = append(ifMatch, r.Header.Get("If-Match"))
+		if r.Header.Get("If-Match") == `"1"` {
+			writeError(w, http.StatusPreconditionFailed, CodeVersionMismatch, "Task was modified")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"status": "success", "data": map[string]int{"task_id": 1, "version": 4}})
+	})
+	ctx := context.Background()
+
+	version, err := c.UpdateTask(ctx, 1, TaskInput{Title: "Task"}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 4, version)
+
+	title := "Patched"
+	_, err = c.PatchTask(ctx, 1, TaskPatch{Title: &title}, AnyVersion)
+	require.NoError(t, err)
+
+	err = c.DeleteTask(ctx, 1, 1)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+
+	assert.Equal(t, []string{`"3"`, "*", `"1"`}, ifMatch)
+}
+
+func TestRetries(t *testing.T) {
+	t.Run("Повтор GET после 503", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"status": "success", "data": Task{ID: 1}})
+		})
+
+		_, err := c.GetTask(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 3, calls.Load())
+	})
+
+	t.Run("Повторы заканчиваются", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		})
+
+		_, err := c.GetTask(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrUnavailable)
+		assert.EqualValues(t, 1+defaultMaxRetries, calls.Load())
+	})
+
+	t.Run("Смена статуса не повторяется после 500", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			writeError(w, http.StatusInternalServerError, CodeServiceUnavailable, "unavailable")
+		})
+
+		_, err := c.TransitionTask(context.Background(), 1, StatusDone, false)
+		assert.ErrorIs(t, err, ErrUnavailable)
+		assert.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("Смена статуса повторяется после 429", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				writeError(w, http.StatusTooManyRequests, CodeRateLimited, "Too many requests")
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"status": "success", "data": map[string]any{"task_id": 1, "status": "done"}})
+		})
+
+		status, err := c.TransitionTask(context.Background(), 1, StatusDone, false)
+		require.NoError(t, err)
+		assert.Equal(t, StatusDone, status)
+		assert.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("Создание повторяется, пока запрос с тем же ключом выполняется", func(t *testing.T) {
+		var keys []string
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+			if len(keys) < 3 {
+				writeError(w, http.StatusConflict, CodeIdempotencyPending, "Request with this Idempotency-Key is still in progress")
+				return
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			writeJSON(w, http.StatusCreated, map[string]any{"status": "success", "data": map[string]int{"task_id": 5}})
+		})
+
+		created, err := c.CreateTask(context.Background(), TaskInput{Title: "Task"}, CreateOptions{IdempotencyKey: "key-1"})
+		require.NoError(t, err)
+		assert.Equal(t, &Created{TaskID: 5, Replayed: true}, created)
+		assert.Equal(t, []string{"key-1", "key-1", "key-1"}, keys)
+	})
+
+	t.Run("Повторное использование ключа не повторяется", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			writeError(w, http.StatusConflict, CodeIdempotencyReused, "Idempotency-Key was already used with a different request")
+		})
+
+		_, err := c.CreateTask(context.Background(), TaskInput{Title: "Task"}, CreateOptions{IdempotencyKey: "key-1"})
+		assert.ErrorIs(t, err, ErrIdempotencyConflict)
+		assert.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("Ошибка клиента не повторяется", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			writeError(w, http.StatusUnauthorized, CodeTokenExpired, "Token is expired")
+		})
+
+		_, err := c.GetTask(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrUnauthorized)
+		assert.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("Отмена контекста прерывает ожидание повтора", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			writeError(w, http.StatusTooManyRequests, CodeRateLimited, "Too many requests")
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := c.GetTask(ctx, 1)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+}
+
+func TestTaskPatchJSON(t *testing.T) {
+	due := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	title := "Task"
+
+	body, err := json.Marshal(TaskPatch{Title: &title, DueAt: &due})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title": "Task", "due_at": "2026-01-02T15:00:00Z"}`, string(body))
+
+	// Очищаемые поля передаются как null, незаданные не передаются
+	body, err = json.Marshal(TaskPatch{ClearDueAt: true, ClearRemindAt: true})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"due_at": null, "remind_at": null}`, string(body))
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Ошибки API. Коды совпадают с dto.Error.Code сервиса
+
+const (
+	CodeFieldBadFormat     = "FIELD_BADFORMAT"
+	CodeFieldIncorrect     = "FIELD_INCORRECT"
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	CodeInvalidTransition  = "INVALID_TRANSITION"
+	CodeTaskNotFound       = "TASK_NOT_FOUND"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeTokenExpired       = "TOKEN_EXPIRED"
+	CodeTokenInvalid       = "TOKEN_INVALID"
+	CodeForbidden          = "FORBIDDEN"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeIdempotencyReused  = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyPending = "IDEMPOTENCY_IN_PROGRESS"
+	CodeVersionMismatch    = "VERSION_MISMATCH"
+	CodePreconditionNeeded = "PRECONDITION_REQUIRED"
+)
+
+// Группы ошибок для errors.Is: errors.Is(err, client.ErrNotFound)
+var (
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrForbidden            = errors.New("forbidden")
+	ErrNotFound             = errors.New("task not found")
+	ErrValidation           = errors.New("invalid request")
+	ErrInvalidTransition    = errors.New("invalid status transition")
+	ErrVersionMismatch      = errors.New("task version mismatch")
+	ErrPreconditionRequired = errors.New("task version is required")
+	ErrIdempotencyConflict  = errors.New("idempotency key conflict")
+	ErrRateLimited          = errors.New("rate limited")
+	ErrUnavailable          = errors.New("service unavailable")
+)
+
+var codeErrors = map[string]error{
+	CodeUnauthorized:       ErrUnauthorized,
+	CodeTokenExpired:       ErrUnauthorized,
+	CodeTokenInvalid:       ErrUnauthorized,
+	CodeForbidden:          ErrForbidden,
+	CodeTaskNotFound:       ErrNotFound,
+	CodeFieldBadFormat:     ErrValidation,
+	CodeFieldIncorrect:     ErrValidation,
+	CodeInvalidTransition:  ErrInvalidTransition,
+	CodeVersionMismatch:    ErrVersionMismatch,
+	CodePreconditionNeeded: ErrPreconditionRequired,
+	CodeIdempotencyReused:  ErrIdempotencyConflict,
+	CodeIdempotencyPending: ErrIdempotencyConflict,
+	CodeRateLimited:        ErrRateLimited,
+	CodeServiceUnavailable: ErrUnavailable,
+}
+
+// APIError - ответ API с ошибкой
+type APIError struct {
+	StatusCode int           // HTTP-статус ответа
+	Code       string        // Код ошибки, пустой, если ответ пришёл не от сервиса (например, от прокси)
+	Desc       string        // Описание ошибки
+	RetryAfter time.Duration // Значение Retry-After для 429
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("unexpected response with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Desc)
+}
+
+// Is - сопоставление кода ошибки с группами ErrNotFound, ErrUnauthorized и т.д.
+// Ответ 5xx без кода относится к ErrUnavailable
+func (e *APIError) Is(target error) bool {
+	if group, ok := codeErrors[e.Code]; ok {
+		return group == target
+	}
+	return e.StatusCode >= 500 && target == ErrUnavailable
+}
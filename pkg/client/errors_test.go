@@ -0,0 +1,54 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"simple-service/internal/dto"
+)
+
+// TestCodesMatchService - коды клиента совпадают с кодами dto.Error сервиса
+func TestCodesMatchService(t *testing.T) {
+	codes := map[string]string{
+		CodeFieldBadFormat:     dto.FieldBadFormat,
+		CodeFieldIncorrect:     dto.FieldIncorrect,
+		CodeServiceUnavailable: dto.ServiceUnavailable,
+		CodeInvalidTransition:  dto.InvalidTransition,
+		CodeTaskNotFound:       dto.TaskNotFound,
+		CodeUnauthorized:       dto.Unauthorized,
+		CodeTokenExpired:       dto.TokenExpired,
+		CodeTokenInvalid:       dto.TokenInvalid,
+		CodeForbidden:          dto.Forbidden,
+		CodeRateLimited:        dto.RateLimited,
+		CodeIdempotencyReused:  dto.IdempotencyReused,
+		CodeIdempotencyPending: dto.IdempotencyPending,
+		CodeVersionMismatch:    dto.VersionMismatch,
+		CodePreconditionNeeded: dto.PreconditionNeeded,
+	}
+	for got, want := range codes {
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *APIError
+		want error
+	}{
+		{name: "истёкший токен", err: &APIError{StatusCode: http.StatusUnauthorized, Code: CodeTokenExpired}, want: ErrUnauthorized},
+		{name: "ошибка валидации", err: &APIError{StatusCode: http.StatusBadRequest, Code: CodeFieldIncorrect}, want: ErrValidation},
+		{name: "повтор ключа идемпотентности", err: &APIError{StatusCode: http.StatusConflict, Code: CodeIdempotencyPending}, want: ErrIdempotencyConflict},
+		{name: "5xx без кода", err: &APIError{StatusCode: http.StatusBadGateway}, want: ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.err, tt.want)
+			assert.False(t, errors.Is(tt.err, ErrNotFound))
+		})
+	}
+}
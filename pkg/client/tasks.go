@@ -0,0 +1,274 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Методы для маршрутов /v1
+
+// TaskStatus - статус задачи
+type TaskStatus string
+
+const (
+	StatusNew        TaskStatus = "new"
+	StatusInProgress TaskStatus = "in_progress"
+	StatusDone       TaskStatus = "done"
+)
+
+// AnyVersion - изменение задачи без проверки версии (If-Match: *)
+const AnyVersion = 0
+
+// Task - задача пользователя
+type Task struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      TaskStatus `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Version     int        `json:"version"`   // Увеличивается при каждом изменении задачи
+	DueAt       *time.Time `json:"due_at"`    // Срок выполнения, nil - без срока
+	RemindAt    *time.Time `json:"remind_at"` // Время напоминания, nil - без напоминания
+}
+
+// TaskInput - поля задачи для создания и полного обновления
+type TaskInput struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	DueAt       *time.Time `json:"due_at,omitempty"`    // Обязателен, если задан RemindAt
+	RemindAt    *time.Time `json:"remind_at,omitempty"` // Не позже DueAt
+}
+
+// TaskPatch - частичное обновление задачи, nil-поля остаются без изменений
+type TaskPatch struct {
+	Title         *string
+	Description   *string
+	DueAt         *time.Time
+	RemindAt      *time.Time
+	ClearDueAt    bool // Убрать срок; напоминание нужно убрать вместе с ним
+	ClearRemindAt bool // Убрать напоминание
+}
+
+// MarshalJSON - тело PATCH: незаданные поля не передаются, очищаемые передаются как null
+func (p TaskPatch) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Title       *string `json:"title,omitempty"`
+		Description *string `json:"description,omitempty"`
+		DueAt       any     `json:"due_at,omitempty"`
+		RemindAt    any     `json:"remind_at,omitempty"`
+	}{
+		Title:       p.Title,
+		Description: p.Description,
+		DueAt:       timePatch(p.DueAt, p.ClearDueAt),
+		RemindAt:    timePatch(p.RemindAt, p.ClearRemindAt),
+	})
+}
+
+// timePatch - значение поля времени в теле PATCH, nil - поле не передаётся
+func timePatch(t *time.Time, clear bool) any {
+	switch {
+	case clear:
+		return json.RawMessage("null")
+	case t != nil:
+		return t
+	}
+	return nil
+}
+
+// CreateOptions - параметры создания задачи
+type CreateOptions struct {
+	// IdempotencyKey - ключ идемпотентности; если не задан, клиент генерирует его сам,
+	// чтобы повтор после сбоя не создал дубль
+	IdempotencyKey string
+}
+
+// Created - результат создания задачи
+type Created struct {
+	TaskID   int
+	Replayed bool // Ответ на повтор запроса с тем же ключом идемпотентности
+}
+
+// ListOptions - фильтры, сортировка и страница списка задач
+type ListOptions struct {
+	Limit       int        // От 1 до 100, по умолчанию 20
+	Sort        string     // created_at, updated_at или title, минус перед полем - по убыванию
+	Status      TaskStatus // Пустой - без фильтра
+	CreatedFrom time.Time  // Включительно, нулевое значение - без фильтра
+	CreatedTo   time.Time  // Не включительно, нулевое значение - без фильтра
+	Cursor      string     // NextCursor предыдущей страницы
+	Overdue     bool       // Только незавершённые задачи с истёкшим сроком
+}
+
+// TaskList - страница задач, NextCursor пустой на последней странице
+type TaskList struct {
+	Tasks      []Task
+	NextCursor string
+}
+
+// SearchOptions - язык и размер выдачи полнотекстового поиска
+type SearchOptions struct {
+	Lang  string // ru (по умолчанию) или en
+	Limit int
+}
+
+// SearchResult - найденная задача с релевантностью и подсвеченными фрагментами
+type SearchResult struct {
+	Task
+	Rank      float32 `json:"rank"`
+	Highlight struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	} `json:"highlight"`
+}
+
+// CreateTask - создание задачи
+func (c *Client) CreateTask(ctx context.Context, input TaskInput, opts CreateOptions) (*Created, error) {
+	key := opts.IdempotencyKey
+	if key == "" {
+		key = newIdempotencyKey()
+	}
+
+	var data struct {
+		TaskID int `json:"task_id"`
+	}
+	resp, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/v1/create_task",
+		header: http.Header{headerIdempotencyKey: {key}},
+		body:   input,
+		safe:   true,
+	}, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &Created{TaskID: data.TaskID, Replayed: resp.header.Get(headerIdempotentReplayed) == "true"}, nil
+}
+
+// GetTask - получение задачи по id
+func (c *Client) GetTask(ctx context.Context, taskID int) (*Task, error) {
+	var task Task
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: taskPath(taskID), safe: true}, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ListTasks - страница задач
+func (c *Client) ListTasks(ctx context.Context, opts ListOptions) (*TaskList, error) {
+	query := url.Values{}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	setQuery(query, "sort", opts.Sort)
+	setQuery(query, "status", string(opts.Status))
+	// Границы отправляются со смещением вызывающего, к UTC их приводит сервис
+	if !opts.CreatedFrom.IsZero() {
+		query.Set("created_from", opts.CreatedFrom.Format(time.RFC3339Nano))
+	}
+	if !opts.CreatedTo.IsZero() {
+		query.Set("created_to", opts.CreatedTo.Format(time.RFC3339Nano))
+	}
+	setQuery(query, "cursor", opts.Cursor)
+	if opts.Overdue {
+		query.Set("overdue", "true")
+	}
+
+	var tasks []Task
+	resp, err := c.do(ctx, request{method: http.MethodGet, path: "/v1/tasks", query: query, safe: true}, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskList{Tasks: tasks, NextCursor: resp.nextCursor}, nil
+}
+
+// SearchTasks - полнотекстовый поиск задач, самые релевантные первыми
+func (c *Client) SearchTasks(ctx context.Context, q string, opts SearchOptions) ([]SearchResult, error) {
+	query := url.Values{"q": {q}}
+	setQuery(query, "lang", opts.Lang)
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	var results []SearchResult
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/v1/tasks/search", query: query, safe: true}, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// UpdateTask - полное обновление задачи версии version (AnyVersion - без проверки), возвращает новую версию
+func (c *Client) UpdateTask(ctx context.Context, taskID int, input TaskInput, version int) (int, error) {
+	var data struct {
+		Version int `json:"version"`
+	}
+	_, err := c.do(ctx, request{
+		method: http.MethodPut,
+		path:   taskPath(taskID),
+		header: ifMatch(version),
+		body:   input,
+		safe:   version == AnyVersion,
+	}, &data)
+	return data.Version, err
+}
+
+// PatchTask - частичное обновление задачи версии version (AnyVersion - без проверки), возвращает новую версию
+func (c *Client) PatchTask(ctx context.Context, taskID int, patch TaskPatch, version int) (int, error) {
+	var data struct {
+		Version int `json:"version"`
+	}
+	_, err := c.do(ctx, request{
+		method: http.MethodPatch,
+		path:   taskPath(taskID),
+		header: ifMatch(version),
+		body:   patch,
+		safe:   version == AnyVersion,
+	}, &data)
+	return data.Version, err
+}
+
+// DeleteTask - удаление задачи версии version (AnyVersion - без проверки)
+func (c *Client) DeleteTask(ctx context.Context, taskID int, version int) error {
+	_, err := c.do(ctx, request{
+		method: http.MethodDelete,
+		path:   taskPath(taskID),
+		header: ifMatch(version),
+		safe:   version == AnyVersion,
+	}, nil)
+	return err
+}
+
+// TransitionTask - смена статуса задачи; reopen разрешает вывести задачу из done
+func (c *Client) TransitionTask(ctx context.Context, taskID int, status TaskStatus, reopen bool) (TaskStatus, error) {
+	var data struct {
+		Status TaskStatus `json:"status"`
+	}
+	_, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   taskPath(taskID) + "/transition",
+		body:   map[string]any{"status": status, "reopen": reopen},
+	}, &data)
+	return data.Status, err
+}
+
+func taskPath(taskID int) string {
+	return "/v1/tasks/" + strconv.Itoa(taskID)
+}
+
+// ifMatch - заголовок If-Match с ожидаемой версией задачи
+func ifMatch(version int) http.Header {
+	if version == AnyVersion {
+		return http.Header{"If-Match": {"*"}}
+	}
+	return http.Header{"If-Match": {strconv.Quote(strconv.Itoa(version))}}
+}
+
+func setQuery(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
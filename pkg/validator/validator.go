@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"regexp"
+	"time"
 
 	"github.com/go-playground/validator"
 )
@@ -19,6 +20,7 @@ const (
 	ErrFieldBelowMinLen   = "Field is below minimum length"
 	ErrFieldExceedsMaxVal = "Field exceeds maximum value"
 	ErrFieldBelowMinVal   = "Field is below minimum value"
+	ErrFieldNotAllowed    = "Field value is not allowed"
 	ErrUnknownValidation  = "Unknown validation error"
 )
 
@@ -29,6 +31,7 @@ func init() {
 func New() *validator.Validate {
 	v := validator.New()
 	_ = v.RegisterValidation("tag", validateTag)
+	_ = v.RegisterValidation("rfc3339", validateRFC3339)
 
 	return v
 }
@@ -46,6 +49,11 @@ func validateTag(fl validator.FieldLevel) bool {
 	return re.MatchString(fl.Field().String())
 }
 
+func validateRFC3339(fl validator.FieldLevel) bool {
+	_, err := time.Parse(time.RFC3339, fl.Field().String())
+	return err == nil
+}
+
 func Validate(ctx context.Context, structure any) error {
 	return parseValidationErrors(Validator().StructCtx(ctx, structure))
 }
@@ -63,18 +71,20 @@ func parseValidationErrors(err error) error {
 	validationError := vErrors[0]
 	var validationErrorDescription string
 	switch validationError.Tag() {
-	case "tag":
+	case "tag", "rfc3339":
 		validationErrorDescription = ErrInvalidFormat
-	case "required":
+	case "required", "required_with":
 		validationErrorDescription = ErrFieldRequired
 	case "max":
 		validationErrorDescription = ErrFieldExceedsMaxLen
 	case "min":
 		validationErrorDescription = ErrFieldBelowMinLen
-	case "lt", "lte":
+	case "lt", "lte", "ltfield", "ltefield":
 		validationErrorDescription = ErrFieldExceedsMaxVal
-	case "gt", "gte":
+	case "gt", "gte", "gtfield", "gtefield":
 		validationErrorDescription = ErrFieldBelowMinVal
+	case "oneof":
+		validationErrorDescription = ErrFieldNotAllowed
 	default:
 		validationErrorDescription = ErrUnknownValidation
 	}
@@ -3,20 +3,28 @@ package validator
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 type TestStruct struct {
-	RequiredField string `validate:"required"`
-	TagField      string `validate:"tag"`
-	MaxField      string `validate:"max=5"`
-	MinField      string `validate:"min=3"`
-	LtField       int    `validate:"lt=10"`
-	GteField      int    `validate:"gte=5"`
+	RequiredField string     `validate:"required"`
+	TagField      string     `validate:"tag"`
+	MaxField      string     `validate:"max=5"`
+	MinField      string     `validate:"min=3"`
+	LtField       int        `validate:"lt=10"`
+	GteField      int        `validate:"gte=5"`
+	OneOfField    string     `validate:"omitempty,oneof=a b"`
+	TimeField     string     `validate:"omitempty,rfc3339"`
+	DueField      *time.Time `validate:"required_with=RemindField"`
+	RemindField   *time.Time `validate:"omitempty,ltefield=DueField"`
 }
 
 func TestValidate(t *testing.T) {
+	early := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+
 	tests := []struct {
 		name       string
 		input      TestStruct
@@ -65,6 +73,36 @@ func TestValidate(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: ErrFieldBelowMinVal + ": TestStruct.GteField",
 		},
+		{
+			name:       "Field value not allowed",
+			input:      TestStruct{RequiredField: "value", TagField: "#tag", MaxField: "value", MinField: "val", LtField: 5, GteField: 5, OneOfField: "c"},
+			wantErr:    true,
+			wantErrMsg: ErrFieldNotAllowed + ": TestStruct.OneOfField",
+		},
+		{
+			name:       "Valid time field",
+			input:      TestStruct{RequiredField: "value", TagField: "#tag", MaxField: "value", MinField: "val", LtField: 5, GteField: 5, TimeField: "2025-01-01T10:00:00Z"},
+			wantErr:    false,
+			wantErrMsg: "",
+		},
+		{
+			name:       "Invalid time field",
+			input:      TestStruct{RequiredField: "value", TagField: "#tag", MaxField: "value", MinField: "val", LtField: 5, GteField: 5, TimeField: "2025-01-01"},
+			wantErr:    true,
+			wantErrMsg: ErrInvalidFormat + ": TestStruct.TimeField",
+		},
+		{
+			name:       "Dependent field is missing",
+			input:      TestStruct{RequiredField: "value", TagField: "#tag", MaxField: "value", MinField: "val", LtField: 5, GteField: 5, RemindField: &early},
+			wantErr:    true,
+			wantErrMsg: ErrFieldRequired + ": TestStruct.DueField",
+		},
+		{
+			name:       "Field exceeds other field",
+			input:      TestStruct{RequiredField: "value", TagField: "#tag", MaxField: "value", MinField: "val", LtField: 5, GteField: 5, DueField: &early, RemindField: &late},
+			wantErr:    true,
+			wantErrMsg: ErrFieldExceedsMaxVal + ": TestStruct.RemindField",
+		},
 	}
 
 	for _, tt := range tests {